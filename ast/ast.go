@@ -2,6 +2,9 @@ package ast
 
 import (
 	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
 
 	"github.com/kevinglasson/monkey/token"
 )
@@ -62,6 +65,138 @@ func (i *Identifier) TokenLiteral() string { return i.Token.Literal }
 
 func (i *Identifier) String() string { return i.Value }
 
+// IntegerLiteral is a struct representing the INT token with it's value.
+type IntegerLiteral struct {
+	Token token.Token
+	Value int64
+}
+
+// expressionNode implements Expression for IntegerLiteral.
+func (il *IntegerLiteral) expressionNode() {}
+
+// TokenLiteral implements Node for IntegerLiteral.
+func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
+
+func (il *IntegerLiteral) String() string { return il.Token.Literal }
+
+// FloatLiteral is a struct representing the FLOAT token with it's value.
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+// expressionNode implements Expression for FloatLiteral.
+func (fl *FloatLiteral) expressionNode() {}
+
+// TokenLiteral implements Node for FloatLiteral.
+func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Literal }
+
+func (fl *FloatLiteral) String() string { return fl.Token.Literal }
+
+// Boolean is a struct representing the TRUE and FALSE tokens with their value.
+type Boolean struct {
+	Token token.Token
+	Value bool
+}
+
+// expressionNode implements Expression for Boolean.
+func (b *Boolean) expressionNode() {}
+
+// TokenLiteral implements Node for Boolean.
+func (b *Boolean) TokenLiteral() string { return b.Token.Literal }
+
+func (b *Boolean) String() string { return b.Token.Literal }
+
+// StringLiteral is a struct representing the STRING token with it's decoded
+// value.
+type StringLiteral struct {
+	Token token.Token
+	Value string
+}
+
+// expressionNode implements Expression for StringLiteral.
+func (sl *StringLiteral) expressionNode() {}
+
+// TokenLiteral implements Node for StringLiteral.
+func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
+
+// String outputs the value as a double quoted string literal, escaping it so
+// that it lexes back to the same value.
+func (sl *StringLiteral) String() string { return quote(sl.Value) }
+
+// quote wraps s in double quotes using the escape sequences understood by the
+// lexer.
+func quote(s string) string {
+	var out strings.Builder
+
+	out.WriteByte('"')
+	writeEscaped(&out, s)
+	out.WriteByte('"')
+
+	return out.String()
+}
+
+// writeEscaped writes s to out using the escape sequences understood by the
+// lexer, without the surrounding quotes.
+func writeEscaped(out *strings.Builder, s string) {
+	for i, r := range s {
+		switch {
+		case r == '$' && strings.HasPrefix(s[i:], "${"):
+			// Would otherwise start an interpolation.
+			out.WriteString(`\$`)
+		case r == '"' || r == '\\':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		case r == '\n':
+			out.WriteString(`\n`)
+		case r == '\r':
+			out.WriteString(`\r`)
+		case r == '\t':
+			out.WriteString(`\t`)
+		case !unicode.IsPrint(r):
+			fmt.Fprintf(out, `\u{%x}`, r)
+		default:
+			out.WriteRune(r)
+		}
+	}
+}
+
+// InterpolatedString is a struct representing a string with ${ } expressions
+// in it. The literal text between the expressions is held as StringLiteral
+// parts, empty text is left out.
+type InterpolatedString struct {
+	// The STRING_HEAD token.
+	Token token.Token
+	Parts []Expression
+}
+
+// expressionNode implements Expression for InterpolatedString.
+func (is *InterpolatedString) expressionNode() {}
+
+// TokenLiteral implements Node for InterpolatedString.
+func (is *InterpolatedString) TokenLiteral() string { return is.Token.Literal }
+
+// String outputs the string with the expressions back inside ${ }.
+func (is *InterpolatedString) String() string {
+	var out strings.Builder
+
+	out.WriteByte('"')
+	for _, part := range is.Parts {
+		if sl, ok := part.(*StringLiteral); ok {
+			writeEscaped(&out, sl.Value)
+			continue
+		}
+		out.WriteString("${")
+		if part != nil {
+			out.WriteString(part.String())
+		}
+		out.WriteString("}")
+	}
+	out.WriteByte('"')
+
+	return out.String()
+}
+
 // LetStatement implements Node for the LET statement.
 type LetStatement struct {
 	// The LET token.
@@ -137,3 +272,369 @@ func (es *ExpressionStatement) String() string {
 
 	return ""
 }
+
+// PrefixExpression is an operator applied to the expression on it's right,
+// such as -5 or !ok.
+type PrefixExpression struct {
+	// The prefix token e.g. ! or -.
+	Token    token.Token
+	Operator string
+	Right    Expression
+}
+
+// expressionNode implements Expression for PrefixExpression.
+func (pe *PrefixExpression) expressionNode() {}
+
+// TokenLiteral implements Node for PrefixExpression.
+func (pe *PrefixExpression) TokenLiteral() string { return pe.Token.Literal }
+
+// String implements part of the Node interface so we can output this
+// expression.
+func (pe *PrefixExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(pe.Operator)
+	if pe.Right != nil {
+		out.WriteString(pe.Right.String())
+	}
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// PostfixExpression is an operator applied to the expression on it's left,
+// such as n! for an operator registered with the parser.
+type PostfixExpression struct {
+	// The postfix token e.g. !.
+	Token    token.Token
+	Left     Expression
+	Operator string
+}
+
+// expressionNode implements Expression for PostfixExpression.
+func (pe *PostfixExpression) expressionNode() {}
+
+// TokenLiteral implements Node for PostfixExpression.
+func (pe *PostfixExpression) TokenLiteral() string { return pe.Token.Literal }
+
+// String implements part of the Node interface so we can output this
+// expression.
+func (pe *PostfixExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	if pe.Left != nil {
+		out.WriteString(pe.Left.String())
+	}
+	out.WriteString(pe.Operator)
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// InfixExpression is an operator applied to the expressions on either side of
+// it, such as 5 + 5.
+type InfixExpression struct {
+	// The operator token e.g. +.
+	Token    token.Token
+	Left     Expression
+	Operator string
+	Right    Expression
+}
+
+// expressionNode implements Expression for InfixExpression.
+func (ie *InfixExpression) expressionNode() {}
+
+// TokenLiteral implements Node for InfixExpression.
+func (ie *InfixExpression) TokenLiteral() string { return ie.Token.Literal }
+
+// String implements part of the Node interface so we can output this
+// expression.
+func (ie *InfixExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	if ie.Left != nil {
+		out.WriteString(ie.Left.String())
+	}
+	out.WriteString(" " + ie.Operator + " ")
+	if ie.Right != nil {
+		out.WriteString(ie.Right.String())
+	}
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// BlockStatement is a sequence of statements enclosed in braces.
+type BlockStatement struct {
+	// The { token.
+	Token      token.Token
+	Statements []Statement
+}
+
+// TokenLiteral implements Node for BlockStatement.
+func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BlockStatement) statementNode()       {}
+
+// String implements part of the Node interface so we can output this statement
+func (bs *BlockStatement) String() string {
+	var out bytes.Buffer
+
+	for _, s := range bs.Statements {
+		out.WriteString(s.String())
+	}
+
+	return out.String()
+}
+
+// IfExpression is a conditional with an optional alternative. An else if
+// chain is represented as an Alternative holding a single nested
+// IfExpression.
+type IfExpression struct {
+	// The IF token.
+	Token       token.Token
+	Condition   Expression
+	Consequence *BlockStatement
+	Alternative *BlockStatement
+}
+
+// expressionNode implements Expression for IfExpression.
+func (ie *IfExpression) expressionNode() {}
+
+// TokenLiteral implements Node for IfExpression.
+func (ie *IfExpression) TokenLiteral() string { return ie.Token.Literal }
+
+// String implements part of the Node interface so we can output this
+// expression.
+func (ie *IfExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("if")
+	if ie.Condition != nil {
+		out.WriteString(ie.Condition.String())
+	}
+	out.WriteString(" ")
+	if ie.Consequence != nil {
+		out.WriteString(ie.Consequence.String())
+	}
+
+	if ie.Alternative != nil {
+		out.WriteString("else ")
+		out.WriteString(ie.Alternative.String())
+	}
+
+	return out.String()
+}
+
+// FunctionLiteral is a fn expression with it's parameters and body.
+type FunctionLiteral struct {
+	// The FUNCTION token.
+	Token      token.Token
+	Parameters []*Identifier
+	Body       *BlockStatement
+}
+
+// expressionNode implements Expression for FunctionLiteral.
+func (fl *FunctionLiteral) expressionNode() {}
+
+// TokenLiteral implements Node for FunctionLiteral.
+func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
+
+// String implements part of the Node interface so we can output this
+// expression.
+func (fl *FunctionLiteral) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range fl.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString(fl.TokenLiteral())
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") ")
+	if fl.Body != nil {
+		out.WriteString(fl.Body.String())
+	}
+
+	return out.String()
+}
+
+// ArrayLiteral is a list of expressions in brackets, e.g. [1, 2 * 3].
+type ArrayLiteral struct {
+	// The [ token.
+	Token    token.Token
+	Elements []Expression
+}
+
+// expressionNode implements Expression for ArrayLiteral.
+func (al *ArrayLiteral) expressionNode() {}
+
+// TokenLiteral implements Node for ArrayLiteral.
+func (al *ArrayLiteral) TokenLiteral() string { return al.Token.Literal }
+
+// String implements part of the Node interface so we can output this
+// expression.
+func (al *ArrayLiteral) String() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+	for _, el := range al.Elements {
+		elements = append(elements, nodeString(el))
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+// HashLiteral is a list of key value pairs in braces, e.g. {"a": 1, 2: true}.
+// The pairs are kept in the order they appear in the source.
+type HashLiteral struct {
+	// The { token.
+	Token token.Token
+	Pairs []HashPair
+}
+
+// HashPair is a key and it's value in a HashLiteral.
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
+// expressionNode implements Expression for HashLiteral.
+func (hl *HashLiteral) expressionNode() {}
+
+// TokenLiteral implements Node for HashLiteral.
+func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
+
+// String implements part of the Node interface so we can output this
+// expression.
+func (hl *HashLiteral) String() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for _, pair := range hl.Pairs {
+		pairs = append(pairs, nodeString(pair.Key)+": "+nodeString(pair.Value))
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// IndexExpression is the lookup of an element, e.g. myArray[1]. Left is any
+// expression that produces something that can be indexed.
+type IndexExpression struct {
+	// The [ token.
+	Token token.Token
+	Left  Expression
+	Index Expression
+}
+
+// expressionNode implements Expression for IndexExpression.
+func (ie *IndexExpression) expressionNode() {}
+
+// TokenLiteral implements Node for IndexExpression.
+func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
+
+// String implements part of the Node interface so we can output this
+// expression.
+func (ie *IndexExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	if ie.Left != nil {
+		out.WriteString(ie.Left.String())
+	}
+	out.WriteString("[")
+	if ie.Index != nil {
+		out.WriteString(ie.Index.String())
+	}
+	out.WriteString("])")
+
+	return out.String()
+}
+
+// CallExpression is the application of a function to a list of arguments. The
+// Function is any expression that produces a function, e.g. an Identifier, a
+// FunctionLiteral or another CallExpression.
+type CallExpression struct {
+	// The ( token.
+	Token     token.Token
+	Function  Expression
+	Arguments []Expression
+}
+
+// expressionNode implements Expression for CallExpression.
+func (ce *CallExpression) expressionNode() {}
+
+// TokenLiteral implements Node for CallExpression.
+func (ce *CallExpression) TokenLiteral() string { return ce.Token.Literal }
+
+// String implements part of the Node interface so we can output this
+// expression.
+func (ce *CallExpression) String() string {
+	var out bytes.Buffer
+
+	args := []string{}
+	for _, a := range ce.Arguments {
+		args = append(args, nodeString(a))
+	}
+
+	if ce.Function != nil {
+		out.WriteString(ce.Function.String())
+	}
+	out.WriteString("(")
+	out.WriteString(strings.Join(args, ", "))
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// AssignExpression is a compound assignment to a variable, such as x += 1.
+type AssignExpression struct {
+	// The assignment token e.g. +=.
+	Token    token.Token
+	Name     *Identifier
+	Operator string
+	Value    Expression
+}
+
+// expressionNode implements Expression for AssignExpression.
+func (ae *AssignExpression) expressionNode() {}
+
+// TokenLiteral implements Node for AssignExpression.
+func (ae *AssignExpression) TokenLiteral() string { return ae.Token.Literal }
+
+// String implements part of the Node interface so we can output this
+// expression.
+func (ae *AssignExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ae.Name.String())
+	out.WriteString(" " + ae.Operator + " ")
+	if ae.Value != nil {
+		out.WriteString(ae.Value.String())
+	}
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// nodeString returns the String of an Expression in a list, or an empty string
+// for a nil Expression left by a parse error.
+func nodeString(e Expression) string {
+	if e == nil {
+		return ""
+	}
+	return e.String()
+}
@@ -19,12 +19,14 @@ func TestString(t *testing.T) {
 						Type:    token.IDENT,
 						Literal: "myVar",
 					},
+					Value: "myVar",
 				},
 				Value: &Identifier{
 					Token: token.Token{
 						Type:    token.IDENT,
 						Literal: "anotherVar",
 					},
+					Value: "anotherVar",
 				},
 			},
 		},
@@ -34,3 +36,24 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() wrong. got=%q", program.String())
 	}
 }
+
+func TestStringLiteralString(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"plain", `"plain"`},
+		{"a\nb\r\tc", `"a\nb\r\tc"`},
+		{`say "hi" \o/`, `"say \"hi\" \\o/"`},
+		{"caf\u00e9 \U0001F600", "\"caf\u00e9 \U0001F600\""},
+		{"bell\a", `"bell\u{7}"`},
+		{"${x} costs $5", `"\${x} costs $5"`},
+	}
+
+	for _, tt := range tests {
+		sl := &StringLiteral{Value: tt.value}
+		if sl.String() != tt.expected {
+			t.Errorf("String() wrong. expected=%q, got=%q", tt.expected, sl.String())
+		}
+	}
+}
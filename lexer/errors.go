@@ -0,0 +1,61 @@
+package lexer
+
+import (
+	"fmt"
+
+	"github.com/kevinglasson/monkey/token"
+)
+
+// Error is a problem found in the input by the Lexer.
+type Error struct {
+	// Pos is where the problem starts.
+	Pos token.Position
+	// Text is the offending input, it may be empty e.g. at the end of the
+	// input.
+	Text string
+	// Reason explains what is wrong, e.g. "unterminated string".
+	Reason string
+}
+
+// Error implements error, formatting the Error as "position: reason".
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Reason)
+}
+
+// error records an Error for the text found at pos.
+func (l *Lexer) error(pos token.Position, text string, format string, a ...interface{}) {
+	l.errors = append(l.errors, &Error{
+		Pos:    pos,
+		Text:   text,
+		Reason: fmt.Sprintf(format, a...),
+	})
+}
+
+// Errors returns all of the errors the Lexer has found so far.
+func (l *Lexer) Errors() []*Error {
+	return l.errors
+}
+
+// ErrorList is a list of Errors, it implements error so that all of the
+// problems found in an input can be returned as one.
+type ErrorList []*Error
+
+// Error implements error, formatting the first Error and how many more there
+// are.
+func (el ErrorList) Error() string {
+	switch len(el) {
+	case 0:
+		return "no errors"
+	case 1:
+		return el[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", el[0], len(el)-1)
+}
+
+// Err returns the ErrorList as an error, or nil if it's empty.
+func (el ErrorList) Err() error {
+	if len(el) == 0 {
+		return nil
+	}
+	return el
+}
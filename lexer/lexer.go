@@ -1,15 +1,66 @@
 package lexer
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
 	"github.com/kevinglasson/monkey/token"
 )
 
 // NextToken examines generates the next Token and advances the indexer.
 func (l *Lexer) NextToken() token.Token {
+	// Skip the whitespace first!
+	leading := l.readTrivia(false)
+	if !l.keepTrivia {
+		tok := l.readToken()
+		tok.End = l.pos()
+		return tok
+	}
+
+	start := l.startCapture()
+	tok := l.readToken()
+	tok.End = l.pos()
+	tok.Raw = l.endCapture(start)
+	tok.Leading = leading
+	tok.Trailing = l.readTrivia(true)
+
+	return tok
+}
+
+// KeepTrivia makes the lexer keep the whitespace and comments around each
+// token as it's Leading and Trailing trivia, along with the Raw text of the
+// token, so that the tokens reproduce the input exactly. It must be called
+// before the first call to NextToken.
+func (l *Lexer) KeepTrivia() {
+	l.keepTrivia = true
+}
+
+// readToken reads the token starting at the current char.
+func (l *Lexer) readToken() token.Token {
 	var tok token.Token
 
-	// Skip the whitespace first!
-	l.skipWhitespace()
+	// The token starts at the current char.
+	pos := l.pos()
+
+	// Checked before the char, as a NUL in the input is also a zero rune.
+	if l.atEOF {
+		if len(l.interpolations) > 0 {
+			l.error(l.interpolations[0].start, "", "unterminated string interpolation")
+			l.interpolations = nil
+		}
+		return token.Token{Type: token.EOF, Pos: pos}
+	}
+
+	if tok, ok := l.readOperator(); ok {
+		tok.Pos = pos
+		l.readChar()
+		return tok
+	}
 
 	// Examine the current char.
 	switch l.ch {
@@ -21,49 +72,82 @@ func (l *Lexer) NextToken() token.Token {
 		tok = newToken(token.RPAREN, l.ch)
 	case ',':
 		tok = newToken(token.COMMA, l.ch)
+	case ':':
+		tok = newToken(token.COLON, l.ch)
+	case '[':
+		tok = newToken(token.LBRACKET, l.ch)
+	case ']':
+		tok = newToken(token.RBRACKET, l.ch)
 	case '{':
 		tok = newToken(token.LBRACE, l.ch)
+		// Braces inside an interpolation are counted so that only the brace
+		// matching the ${ ends it.
+		if n := len(l.interpolations); n > 0 {
+			l.interpolations[n-1].depth++
+		}
 	case '}':
+		if n := len(l.interpolations); n > 0 && l.interpolations[n-1].depth == 0 {
+			// The end of an interpolation, carry on with the string.
+			var interpolated bool
+			tok.Literal, interpolated = l.readString(l.interpolations[n-1].start)
+			if interpolated {
+				tok.Type = token.STRING_MIDDLE
+			} else {
+				tok.Type = token.STRING_TAIL
+				l.interpolations = l.interpolations[:n-1]
+			}
+			break
+		} else if n > 0 {
+			l.interpolations[n-1].depth--
+		}
 		tok = newToken(token.RBRACE, l.ch)
 	case '+':
-		tok = newToken(token.PLUS, l.ch)
+		tok = l.newPairToken('=', token.PLUS_ASSIGN, token.PLUS)
 	case '-':
-		tok = newToken(token.MINUS, l.ch)
+		tok = l.newPairToken('=', token.MINUS_ASSIGN, token.MINUS)
 	case '/':
-		tok = newToken(token.SLASH, l.ch)
+		tok = l.newPairToken('=', token.SLASH_ASSIGN, token.SLASH)
 	case '*':
-		tok = newToken(token.ASTERISK, l.ch)
+		if l.peekChar() == '*' {
+			tok = l.newPairToken('*', token.POWER, token.ASTERISK)
+		} else {
+			tok = l.newPairToken('=', token.ASTERISK_ASSIGN, token.ASTERISK)
+		}
+	case '%':
+		tok = newToken(token.PERCENT, l.ch)
 	case '<':
-		tok = newToken(token.LT, l.ch)
+		if l.peekChar() == '<' {
+			tok = l.newPairToken('<', token.SHL, token.LT)
+		} else {
+			tok = l.newPairToken('=', token.LTE, token.LT)
+		}
 	case '>':
-		tok = newToken(token.GT, l.ch)
-	case '=':
-		// If the next char is an '=' then we have and '=='
-		if l.peekChar() == '=' {
-			// Combine the current char and the next one.
-			ch := l.ch
-			l.readChar()
-			literal := string(ch) + string(l.ch)
-			tok = token.Token{Type: token.EQ, Literal: literal}
-			// Otherwise it's just an assignment
+		if l.peekChar() == '>' {
+			tok = l.newPairToken('>', token.SHR, token.GT)
 		} else {
-			tok = newToken(token.ASSIGN, l.ch)
+			tok = l.newPairToken('=', token.GTE, token.GT)
 		}
+	case '=':
+		// Either '==' or just an assignment.
+		tok = l.newPairToken('=', token.EQ, token.ASSIGN)
 	case '!':
-		// If the next char is an '=' then we have and '!='
-		if l.peekChar() == '=' {
-			ch := l.ch
-			l.readChar()
-			literal := string(ch) + string(l.ch)
-			tok = token.Token{Type: token.NEQ, Literal: literal}
+		// Either '!=' or just a not.
+		tok = l.newPairToken('=', token.NEQ, token.BANG)
+	case '&':
+		tok = l.newPairToken('&', token.AND, token.AMPERSAND)
+	case '|':
+		tok = l.newPairToken('|', token.OR, token.PIPE)
+	case '^':
+		tok = newToken(token.CARET, l.ch)
+	case '"':
+		var interpolated bool
+		tok.Literal, interpolated = l.readString(pos)
+		if interpolated {
+			tok.Type = token.STRING_HEAD
+			l.interpolations = append(l.interpolations, interpolation{start: pos})
 		} else {
-			tok = newToken(token.BANG, l.ch)
+			tok.Type = token.STRING
 		}
-	// We actually set this as the current char when we've reached the end of
-	// the input string.
-	case 0:
-		tok.Literal = ""
-		tok.Type = token.EOF
 	default:
 		// If we have a letter.
 		if isLetter(l.ch) {
@@ -72,95 +156,582 @@ func (l *Lexer) NextToken() token.Token {
 			// Determine it's type i.e. is it a keyword or just a user defined
 			// identifier (variable name etc).
 			tok.Type = token.LookupIdent(tok.Literal)
+			if t, ok := l.words[tok.Literal]; ok {
+				tok.Type = t
+			}
+			tok.Pos = pos
 			// Return early as we have already advanced the char indexer.
 			return tok
 		} else if isDigit(l.ch) {
-			tok.Type = token.INT
-			tok.Literal = l.readNumber()
+			tok = l.readNumber()
+			tok.Pos = pos
 			return tok
 		}
-		tok = newToken(token.ILLEGAL, l.ch)
+		// Use the raw input so that invalid UTF-8 is kept as is.
+		tok = token.Token{Type: token.ILLEGAL, Literal: string(l.raw[:l.rawLen])}
+		if l.ch == utf8.RuneError && l.rawLen == 1 {
+			l.error(pos, tok.Literal, "invalid UTF-8 encoding")
+		} else {
+			l.error(pos, tok.Literal, "unexpected character %q", l.ch)
+		}
 	}
+	tok.Pos = pos
 	l.readChar()
 	return tok
 }
 
-// skipWhitespace advances the char indexer until the whitespace is done.
-func (l *Lexer) skipWhitespace() {
-	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
+// pos returns the Position of the current char.
+func (l *Lexer) pos() token.Position {
+	return token.Position{
+		Filename: l.filename,
+		Offset:   l.position,
+		Line:     l.line,
+		Column:   l.position - l.lineStart + 1,
+	}
+}
+
+// readTrivia advances the char indexer past the whitespace and comments before
+// a token. If the lexer keeps trivia they are returned, otherwise they are
+// thrown away and nil is returned. If trailing is set it stops after the first
+// newline, so that the rest of the trivia belongs to the next token.
+func (l *Lexer) readTrivia(trailing bool) []token.Trivia {
+	var trivia []token.Trivia
+
+	for {
+		kind := l.triviaKind()
+		if kind == "" {
+			return trivia
+		}
+
+		var start int
+		if l.keepTrivia {
+			start = l.startCapture()
+		}
+
+		switch kind {
+		case token.WHITESPACE:
+			for l.triviaKind() == token.WHITESPACE {
+				l.readChar()
+			}
+		case token.NEWLINE:
+			if l.ch == '\r' {
+				l.readChar()
+			}
+			l.readChar()
+		case token.LINE_COMMENT, token.SHEBANG:
+			l.skipLineComment()
+		case token.BLOCK_COMMENT:
+			l.skipBlockComment()
+		}
+
+		if l.keepTrivia {
+			trivia = append(trivia, token.Trivia{Kind: kind, Text: l.endCapture(start)})
+		}
+
+		if trailing && kind == token.NEWLINE {
+			return trivia
+		}
+	}
+}
+
+// triviaKind returns the kind of trivia starting at the current char, or an
+// empty kind if there isn't any.
+func (l *Lexer) triviaKind() token.TriviaKind {
+	switch {
+	case l.ch == ' ' || l.ch == '\t' || l.ch == '\r' && l.peekChar() != '\n':
+		return token.WHITESPACE
+	case l.ch == '\n' || l.ch == '\r':
+		return token.NEWLINE
+	case l.ch == '/' && l.peekChar() == '/':
+		return token.LINE_COMMENT
+	case l.ch == '/' && l.peekChar() == '*':
+		return token.BLOCK_COMMENT
+	// A #! line at the very start of the input, so that Monkey files can be
+	// made executable.
+	case l.position == 0 && l.ch == '#' && l.peekChar() == '!':
+		return token.SHEBANG
+	}
+	return ""
+}
+
+// skipLineComment advances the char indexer to the newline ending the
+// comment, or the end of the input.
+func (l *Lexer) skipLineComment() {
+	for l.ch != '\n' && !l.atEOF {
+		l.readChar()
+	}
+}
+
+// skipBlockComment advances the char indexer past a /* */ comment starting at
+// the current char. Block comments nest, so every /* needs a matching */.
+func (l *Lexer) skipBlockComment() {
+	start := l.pos()
+	depth := 0
+
+	for {
+		switch {
+		case l.atEOF:
+			l.error(start, "/*", "unterminated block comment")
+			return
+		case l.ch == '/' && l.peekChar() == '*':
+			depth++
+			l.readChar()
+		case l.ch == '*' && l.peekChar() == '/':
+			depth--
+			l.readChar()
+			if depth == 0 {
+				l.readChar()
+				return
+			}
+		}
 		l.readChar()
 	}
 }
 
 // peekChar looks at the next char in the input without advancing the indexer.
-func (l *Lexer) peekChar() byte {
+func (l *Lexer) peekChar() rune {
+	b := l.peekBytes()
 	// If the next char will be the end of the input the return 0.
-	if l.readPosition >= len(l.input) {
+	if len(b) == 0 {
 		return 0
 	}
 
-	return l.input[l.readPosition]
+	r, _ := utf8.DecodeRune(b)
+	return r
+}
+
+// startCapture starts collecting the text of the input from the current char.
+// Captures can be nested, the returned start must be passed to the matching
+// endCapture.
+func (l *Lexer) startCapture() int {
+	l.capturing++
+	return l.captured.Len()
+}
+
+// endCapture stops collecting the text of the input and returns the text from
+// where the capture started up to, but not including, the current char.
+func (l *Lexer) endCapture(start int) string {
+	text := l.captured.String()[start:]
+	l.capturing--
+	if l.capturing == 0 {
+		l.captured.Reset()
+	}
+	return text
+}
+
+// newPairToken returns a two char token of pairType if the next char is
+// next, combining it with the current char, and a single char token of
+// singleType otherwise.
+func (l *Lexer) newPairToken(next rune, pairType, singleType token.TokenType) token.Token {
+	if l.peekChar() != next {
+		return newToken(singleType, l.ch)
+	}
+
+	ch := l.ch
+	l.readChar()
+	return token.Token{Type: pairType, Literal: string(ch) + string(l.ch)}
 }
 
-func newToken(tokenType token.TokenType, ch byte) token.Token {
+func newToken(tokenType token.TokenType, ch rune) token.Token {
 	return token.Token{Type: tokenType, Literal: string(ch)}
 }
 
 func (l *Lexer) readIdentifier() string {
-	startPos := l.position
+	start := l.startCapture()
 	// Loop while we have letters.
-	for isLetter(l.ch) {
+	for isLetter(l.ch) || isIdentifierPart(l.ch) {
 		l.readChar()
 	}
-	return l.input[startPos:l.position]
+	return l.endCapture(start)
 }
 
-// isLetter determines if a character is a letter (or an underscore).
-func isLetter(ch byte) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+// isLetter determines if a character can start an identifier, that is a
+// Unicode letter or letter number (ID_Start), or an underscore.
+func isLetter(ch rune) bool {
+	if ch < utf8.RuneSelf {
+		return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+	}
+	return unicode.IsLetter(ch) || unicode.Is(unicode.Nl, ch)
+}
+
+// isIdentifierPart determines if a character can continue an identifier on
+// top of those that can start one, that is a decimal digit, a combining mark
+// or a connector punctuation (ID_Continue).
+func isIdentifierPart(ch rune) bool {
+	if ch < utf8.RuneSelf {
+		return isDigit(ch)
+	}
+	return unicode.In(ch, unicode.Nd, unicode.Mn, unicode.Mc, unicode.Pc)
 }
 
-func (l *Lexer) readNumber() string {
-	startPos := l.position
-	for isDigit(l.ch) {
+// readNumber reads an INT or FLOAT literal. Integers may have a 0x, 0b or 0o
+// base prefix, floats have a fraction, an exponent or both. Digits can be
+// separated by single underscores. A malformed literal is returned as an
+// ILLEGAL token.
+func (l *Lexer) readNumber() token.Token {
+	start := l.pos()
+	tokType := token.TokenType(token.INT)
+	var msg string
+
+	capture := l.startCapture()
+
+	if base, name := basePrefix(l.ch, l.peekChar()); base != 0 {
+		// Skip the prefix.
+		l.readChar()
+		l.readChar()
+		if l.readDigits(base, name, &msg) == 0 && msg == "" {
+			msg = name + " literal has no digits"
+		}
+	} else {
+		l.readDigits(10, "decimal", &msg)
+
+		// A fraction needs a digit after the dot.
+		if l.ch == '.' && isDigit(l.peekChar()) {
+			tokType = token.FLOAT
+			l.readChar()
+			l.readDigits(10, "decimal", &msg)
+		}
+
+		if l.ch == 'e' || l.ch == 'E' {
+			tokType = token.FLOAT
+			l.readChar()
+			if l.ch == '+' || l.ch == '-' {
+				l.readChar()
+			}
+			if l.readDigits(10, "decimal", &msg) == 0 && msg == "" {
+				msg = "exponent has no digits"
+			}
+		}
+	}
+
+	literal := l.endCapture(capture)
+	if msg != "" {
+		l.error(start, literal, "%s", msg)
+		return token.Token{Type: token.ILLEGAL, Literal: literal}
+	}
+
+	return token.Token{Type: tokType, Literal: literal}
+}
+
+// basePrefix returns the base and it's name if ch and next are an integer
+// base prefix, otherwise it returns a zero base.
+func basePrefix(ch, next rune) (int, string) {
+	if ch != '0' {
+		return 0, ""
+	}
+
+	switch next {
+	case 'x', 'X':
+		return 16, "hexadecimal"
+	case 'b', 'B':
+		return 2, "binary"
+	case 'o', 'O':
+		return 8, "octal"
+	}
+
+	return 0, ""
+}
+
+// readDigits reads a run of digits in the given base, which may be separated
+// by single underscores, and returns the number of digits read. All decimal
+// digits are read whatever the base so that a digit out of range is reported
+// rather than starting a new token. Only the first problem is stored in msg.
+func (l *Lexer) readDigits(base int, name string, msg *string) int {
+	setMsg := func(format string, a ...interface{}) {
+		if *msg == "" {
+			*msg = fmt.Sprintf(format, a...)
+		}
+	}
+
+	n := 0
+	separated := false
+
+	for {
+		switch {
+		case l.ch == '_':
+			if n == 0 || separated {
+				setMsg("'_' must separate successive digits")
+			}
+			separated = true
+		case isDigit(l.ch) || base == 16 && isHexDigit(l.ch):
+			if base < 10 && int(l.ch-'0') >= base {
+				setMsg("invalid digit %q in %s literal", l.ch, name)
+			}
+			n++
+			separated = false
+		default:
+			if separated {
+				setMsg("'_' must separate successive digits")
+			}
+			return n
+		}
 		l.readChar()
 	}
-	return l.input[startPos:l.position]
 }
 
-func isDigit(ch byte) bool {
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
+func isHexDigit(ch rune) bool {
+	return isDigit(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
+
+// readString reads a double quoted string, or the rest of one after an
+// interpolation, starting at the opening quote or the } ending the
+// interpolation. It returns the value with the escape sequences decoded, up to
+// the closing quote or the next ${, and whether it stopped at a ${. The closing
+// quote, or the { of the ${, is left as the current char. start is where the
+// string began, for reporting errors.
+func (l *Lexer) readString(start token.Position) (string, bool) {
+	var out strings.Builder
+	capture := l.startCapture()
+
+	for {
+		l.readChar()
+
+		if l.atEOF {
+			// Keep what we have so the parser can carry on.
+			l.error(start, l.endCapture(capture), "unterminated string")
+			return out.String(), false
+		}
+
+		switch l.ch {
+		case '"':
+			l.endCapture(capture)
+			return out.String(), false
+		case '$':
+			if l.peekChar() == '{' {
+				l.readChar()
+				l.endCapture(capture)
+				return out.String(), true
+			}
+			out.WriteByte('$')
+		case '\\':
+			l.readEscape(&out)
+		default:
+			// Copy the raw input so that invalid UTF-8 is kept as is.
+			out.Write(l.raw[:l.rawLen])
+		}
+	}
+}
+
+// readEscape decodes the escape sequence starting at the current backslash
+// into out. The last char of the sequence is left as the current char.
+func (l *Lexer) readEscape(out *strings.Builder) {
+	pos := l.pos()
+	l.readChar()
+
+	// Let readString report the unterminated string.
+	if l.atEOF {
+		return
+	}
+
+	switch l.ch {
+	case 'n':
+		out.WriteByte('\n')
+	case 'r':
+		out.WriteByte('\r')
+	case 't':
+		out.WriteByte('\t')
+	case '"':
+		out.WriteByte('"')
+	case '\\':
+		out.WriteByte('\\')
+	case '$':
+		out.WriteByte('$')
+	case 'u':
+		l.readUnicodeEscape(pos, out)
+	default:
+		l.error(pos, "\\"+string(l.ch), "unknown escape sequence \\%c", l.ch)
+	}
+}
+
+// readUnicodeEscape decodes a \u{X} escape, where X is 1 to 6 hex digits,
+// starting at the current 'u'.
+func (l *Lexer) readUnicodeEscape(pos token.Position, out *strings.Builder) {
+	text := `\u`
+	if l.peekChar() != '{' {
+		l.error(pos, text, "invalid unicode escape, expected \\u{...}")
+		return
+	}
+	l.readChar()
+	text += "{"
+
+	var digits strings.Builder
+	for isHexDigit(l.peekChar()) {
+		l.readChar()
+		digits.WriteRune(l.ch)
+	}
+	text += digits.String()
+
+	if l.peekChar() != '}' || digits.Len() == 0 || digits.Len() > 6 {
+		l.error(pos, text, "invalid unicode escape, expected \\u{...}")
+		return
+	}
+	l.readChar()
+	text += "}"
+
+	// At most 6 hex digits always fits.
+	v, _ := strconv.ParseInt(digits.String(), 16, 32)
+	r := rune(v)
+	if !utf8.ValidRune(r) {
+		l.error(pos, text, "invalid unicode code point U+%04X", r)
+		return
+	}
+	out.WriteRune(r)
+}
+
+// interpolation is a ${ } being lexed inside a string.
+type interpolation struct {
+	// start is the position of the opening quote of the string.
+	start token.Position
+	// depth is the number of braces open inside the interpolation.
+	depth int
+}
+
+// bufferSize is the size of the buffer used to read the input, so only this
+// much of the input is held in memory at once, plus the token being read.
+const bufferSize = 4096
+
 // Lexer is the languages lexer struct.
 type Lexer struct {
-	// input is the input string to lex.
-	input string
-	// position is the current position in the input (current char).
+	// r is the input to lex.
+	r *bufio.Reader
+	// failed is set when reading the input has failed, no more is read after
+	// that.
+	failed  bool
+	readErr error
+	// atEOF is set once the end of the input has been reached.
+	atEOF bool
+	// position is the current byte position in the input (current char).
 	position int
-	// readPosition is the reading position in the input (next char).
+	// readPosition is the byte reading position in the input (next char).
 	readPosition int
 	// ch is the current char under examination.
-	ch byte
+	ch rune
+	// raw holds the UTF-8 encoding of ch as it appeared in the input, which
+	// for invalid UTF-8 is a single byte.
+	raw [utf8.UTFMax]byte
+	// rawLen is the number of bytes in raw.
+	rawLen int
+
+	// capturing is the number of captures in progress, while there are any
+	// the text of the input being read is collected in captured.
+	capturing int
+	captured  strings.Builder
+
+	// filename is the optional name of the file the input came from.
+	filename string
+	// line is the line number of the current char.
+	line int
+	// lineStart is the position of the first char of the current line.
+	lineStart int
+
+	// keepTrivia is set when the whitespace and comments around the tokens
+	// are kept.
+	keepTrivia bool
+
+	// interpolations are the ${ } currently being lexed inside strings, the
+	// innermost last.
+	interpolations []interpolation
+
+	// operators are the declared symbol operators, longest first, and words
+	// are the declared word operators. See DeclareOperator.
+	operators []operator
+	words     map[string]token.TokenType
+
+	// errors are the errors found so far.
+	errors []*Error
 }
 
 // New creates a new lexer for an input string.
 func New(input string) *Lexer {
+	return NewFile("", input)
+}
+
+// NewFile creates a new lexer for an input string that came from the named
+// file, the file name is recorded in the Position of every token.
+func NewFile(filename, input string) *Lexer {
+	return NewFileReader(filename, strings.NewReader(input))
+}
+
+// NewReader creates a new lexer that reads it's input from r as it goes, so
+// the input doesn't need to be held in memory all at once. The tokens are the
+// same as those from New for the same input.
+func NewReader(r io.Reader) *Lexer {
+	return NewFileReader("", r)
+}
+
+// NewFileReader creates a new lexer that reads it's input from r, which came
+// from the named file, the file name is recorded in the Position of every
+// token.
+func NewFileReader(filename string, r io.Reader) *Lexer {
 	// Init a lexer.
-	l := &Lexer{input: input}
-	// Read teh first char of the string.
+	l := &Lexer{r: bufio.NewReaderSize(r, bufferSize), filename: filename, line: 1}
+	// Read teh first char of the input.
 	l.readChar()
 	return l
 }
 
+// peekBytes returns up to utf8.UTFMax bytes of the input after the current
+// char, fewer near the end of the input. A failed read ends the input, it is
+// reported once the lexer gets there.
+func (l *Lexer) peekBytes() []byte {
+	if l.failed {
+		// Only what was read before the failure is left.
+		b, _ := l.r.Peek(l.r.Buffered())
+		if len(b) > utf8.UTFMax {
+			b = b[:utf8.UTFMax]
+		}
+		return b
+	}
+
+	b, err := l.r.Peek(utf8.UTFMax)
+	if err != nil && err != io.EOF {
+		l.failed = true
+		l.readErr = err
+	}
+	return b
+}
+
 func (l *Lexer) readChar() {
-	if l.readPosition >= len(l.input) {
+	// Stay put once the end of the input has been reached, so that every EOF
+	// token has the same position.
+	if l.atEOF {
+		return
+	}
+
+	// Collect the char being moved past if the text is being captured.
+	if l.capturing > 0 {
+		l.captured.Write(l.raw[:l.rawLen])
+	}
+
+	// Moving past a newline starts a new line.
+	if l.ch == '\n' {
+		l.line++
+		l.lineStart = l.readPosition
+	}
+
+	// Chars are UTF-8 encoded so may be more than one byte wide, invalid UTF-8
+	// is read a byte at a time as utf8.RuneError.
+	b := l.peekBytes()
+	width := 0
+	if len(b) == 0 {
 		l.ch = 0
+		l.atEOF = true
 	} else {
 		// Read the next char
-		l.ch = l.input[l.readPosition]
+		l.ch, width = utf8.DecodeRune(b)
+		copy(l.raw[:], b[:width])
+		l.r.Discard(width)
 	}
+	l.rawLen = width
 	// Advance the current, and next char indexes
 	l.position = l.readPosition
-	l.readPosition++
+	l.readPosition += width
+
+	if l.atEOF && l.failed {
+		l.error(l.pos(), "", "read error: %s", l.readErr)
+	}
 }
@@ -15,7 +15,7 @@ let ten = 10;
 };
 
 let result = add(five, ten);
-!-/*5;
+!-/ *5;
 5 < 10 > 5;
 
 if (5 < 10) {
@@ -26,6 +26,8 @@ if (5 < 10) {
 
 10 == 10;
 10 != 9;
+[1, 2];
+{"foo": "bar"}
 `
 
 	tests := []struct {
@@ -105,6 +107,17 @@ if (5 < 10) {
 		{token.NEQ, "!="},
 		{token.INT, "9"},
 		{token.SEMICOLON, ";"},
+		{token.LBRACKET, "["},
+		{token.INT, "1"},
+		{token.COMMA, ","},
+		{token.INT, "2"},
+		{token.RBRACKET, "]"},
+		{token.SEMICOLON, ";"},
+		{token.LBRACE, "{"},
+		{token.STRING, "foo"},
+		{token.COLON, ":"},
+		{token.STRING, "bar"},
+		{token.RBRACE, "}"},
 
 		{token.EOF, ""},
 	}
@@ -129,3 +142,562 @@ if (5 < 10) {
 		}
 	}
 }
+
+func TestTokenPositions(t *testing.T) {
+	input := `let five = 5;
+  five ==
+	10
+`
+
+	tests := []struct {
+		expectedType   token.TokenType
+		expectedOffset int
+		expectedLine   int
+		expectedColumn int
+		// expectedEnd is the offset of the end of the token.
+		expectedEnd int
+	}{
+		{token.LET, 0, 1, 1, 3},
+		{token.IDENT, 4, 1, 5, 8},
+		{token.ASSIGN, 9, 1, 10, 10},
+		{token.INT, 11, 1, 12, 12},
+		{token.SEMICOLON, 12, 1, 13, 13},
+		{token.IDENT, 16, 2, 3, 20},
+		{token.EQ, 21, 2, 8, 23},
+		{token.INT, 25, 3, 2, 27},
+		{token.EOF, 28, 4, 1, 28},
+		{token.EOF, 28, 4, 1, 28},
+	}
+
+	l := NewFile("five.mk", input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong expected=%q, got %q", i, tt.expectedType, tok.Type)
+		}
+
+		expected := token.Position{
+			Filename: "five.mk",
+			Offset:   tt.expectedOffset,
+			Line:     tt.expectedLine,
+			Column:   tt.expectedColumn,
+		}
+		if tok.Pos != expected {
+			t.Fatalf("tests[%d] - position wrong expected=%+v, got %+v", i, expected, tok.Pos)
+		}
+		if tok.End.Offset != tt.expectedEnd {
+			t.Fatalf("tests[%d] - end offset wrong expected=%d, got %d", i, tt.expectedEnd, tok.End.Offset)
+		}
+	}
+}
+
+func TestPositionString(t *testing.T) {
+	tests := []struct {
+		pos      token.Position
+		expected string
+	}{
+		{token.Position{Filename: "a.mk", Offset: 4, Line: 2, Column: 3}, "a.mk:2:3"},
+		{token.Position{Offset: 4, Line: 2, Column: 3}, "2:3"},
+		{token.Position{Filename: "a.mk"}, "a.mk"},
+		{token.Position{}, "-"},
+	}
+
+	for _, tt := range tests {
+		if tt.pos.String() != tt.expected {
+			t.Errorf("wrong string. expected=%q, got=%q", tt.expected, tt.pos.String())
+		}
+	}
+}
+
+func TestStrings(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedLiteral string
+	}{
+		{`"foobar"`, "foobar"},
+		{`"foo bar"`, "foo bar"},
+		{`""`, ""},
+		{`"a\nb\tc"`, "a\nb\tc"},
+		{`"say \"hi\""`, `say "hi"`},
+		{`"back\\slash"`, `back\slash`},
+		{`"\u{48}\u{e9}\u{1F600}"`, "H\u00e9\U0001F600"},
+		{"\"caf\u00e9\"", "caf\u00e9"},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != token.STRING {
+			t.Fatalf("tests[%d] - tokentype wrong expected=%q, got %q", i, token.STRING, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong expected=%q, got %q", i, tt.expectedLiteral, tok.Literal)
+		}
+		if len(l.Errors()) != 0 {
+			t.Fatalf("tests[%d] - unexpected errors %q", i, l.Errors())
+		}
+		if tok := l.NextToken(); tok.Type != token.EOF {
+			t.Fatalf("tests[%d] - expected EOF, got %q", i, tok.Type)
+		}
+	}
+}
+
+func TestStringErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedLiteral string
+		expectedError   string
+	}{
+		{`"abc`, "abc", "1:1: unterminated string"},
+		{`x = "abc\`, "abc", "1:5: unterminated string"},
+		{`"a\qb"`, "ab", `1:3: unknown escape sequence \q`},
+		{`"\u41"`, "41", `1:2: invalid unicode escape, expected \u{...}`},
+		{`"\u{}"`, "}", `1:2: invalid unicode escape, expected \u{...}`},
+		{`"\u{D800}"`, "", "1:2: invalid unicode code point U+D800"},
+		{`"\u{110000}"`, "", "1:2: invalid unicode code point U+110000"},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+
+		tok := l.NextToken()
+		for tok.Type != token.STRING && tok.Type != token.EOF {
+			tok = l.NextToken()
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Errorf("tests[%d] - literal wrong expected=%q, got %q", i, tt.expectedLiteral, tok.Literal)
+		}
+		if len(l.Errors()) != 1 {
+			t.Fatalf("tests[%d] - expected 1 error, got %q", i, l.Errors())
+		}
+		if l.Errors()[0].Error() != tt.expectedError {
+			t.Errorf("tests[%d] - error wrong expected=%q, got %q", i, tt.expectedError, l.Errors()[0])
+		}
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []token.Token
+	}{
+		{
+			`"hello ${name}, you have ${count + 1} items"`,
+			[]token.Token{
+				{Type: token.STRING_HEAD, Literal: "hello "},
+				{Type: token.IDENT, Literal: "name"},
+				{Type: token.STRING_MIDDLE, Literal: ", you have "},
+				{Type: token.IDENT, Literal: "count"},
+				{Type: token.PLUS, Literal: "+"},
+				{Type: token.INT, Literal: "1"},
+				{Type: token.STRING_TAIL, Literal: " items"},
+			},
+		},
+		{
+			`"${fn() { "in ${x}" }()}"`,
+			[]token.Token{
+				{Type: token.STRING_HEAD, Literal: ""},
+				{Type: token.FUNCTION, Literal: "fn"},
+				{Type: token.LPAREN, Literal: "("},
+				{Type: token.RPAREN, Literal: ")"},
+				{Type: token.LBRACE, Literal: "{"},
+				{Type: token.STRING_HEAD, Literal: "in "},
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.STRING_TAIL, Literal: ""},
+				{Type: token.RBRACE, Literal: "}"},
+				{Type: token.LPAREN, Literal: "("},
+				{Type: token.RPAREN, Literal: ")"},
+				{Type: token.STRING_TAIL, Literal: ""},
+			},
+		},
+		{
+			`"\${x} $y {}" }`,
+			[]token.Token{
+				{Type: token.STRING, Literal: "${x} $y {}"},
+				{Type: token.RBRACE, Literal: "}"},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+
+		for j, expected := range tt.expected {
+			tok := l.NextToken()
+			if tok.Type != expected.Type || tok.Literal != expected.Literal {
+				t.Fatalf("tests[%d][%d] - token wrong. expected=%s %q, got=%s %q",
+					i, j, expected.Type, expected.Literal, tok.Type, tok.Literal)
+			}
+		}
+		if tok := l.NextToken(); tok.Type != token.EOF {
+			t.Fatalf("tests[%d] - expected EOF, got %q", i, tok.Type)
+		}
+		if len(l.Errors()) != 0 {
+			t.Fatalf("tests[%d] - unexpected errors %q", i, l.Errors())
+		}
+	}
+}
+
+func TestStringInterpolationErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`x = "a ${b`, "1:5: unterminated string interpolation"},
+		{`"a ${"b ${c`, "1:1: unterminated string interpolation"},
+		{`"a ${b} c`, "1:1: unterminated string"},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		}
+
+		if len(l.Errors()) != 1 {
+			t.Fatalf("tests[%d] - expected 1 error, got %q", i, l.Errors())
+		}
+		if l.Errors()[0].Error() != tt.expectedError {
+			t.Errorf("tests[%d] - error wrong expected=%q, got %q", i, tt.expectedError, l.Errors()[0])
+		}
+	}
+}
+
+func TestUnicode(t *testing.T) {
+	input := "let café = \"crème brûlée 🍮\";\nlet π2 = ñ_1 + x\u0301 + Ⅻ;\n🍮 \xff"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedOffset  int
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{token.LET, "let", 0, 1, 1},
+		{token.IDENT, "café", 4, 1, 5},
+		{token.ASSIGN, "=", 10, 1, 11},
+		{token.STRING, "crème brûlée 🍮", 12, 1, 13},
+		{token.SEMICOLON, ";", 34, 1, 35},
+		{token.LET, "let", 36, 2, 1},
+		{token.IDENT, "π2", 40, 2, 5},
+		{token.ASSIGN, "=", 44, 2, 9},
+		{token.IDENT, "ñ_1", 46, 2, 11},
+		{token.PLUS, "+", 51, 2, 16},
+		{token.IDENT, "x\u0301", 53, 2, 18},
+		{token.PLUS, "+", 57, 2, 22},
+		{token.IDENT, "Ⅻ", 59, 2, 24},
+		{token.SEMICOLON, ";", 62, 2, 27},
+		{token.ILLEGAL, "🍮", 64, 3, 1},
+		{token.ILLEGAL, "\xff", 69, 3, 6},
+		{token.EOF, "", 70, 3, 7},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong expected=%q, got %q. Literal was %q", i, tt.expectedType, tok.Type, tok.Literal)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong expected=%q, got %q", i, tt.expectedLiteral, tok.Literal)
+		}
+
+		expected := token.Position{Offset: tt.expectedOffset, Line: tt.expectedLine, Column: tt.expectedColumn}
+		if tok.Pos != expected {
+			t.Fatalf("tests[%d] - position wrong expected=%+v, got %+v", i, expected, tok.Pos)
+		}
+	}
+}
+
+func TestIdentifiersWithDigits(t *testing.T) {
+	input := "x1 1x _9"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "x1"},
+		{token.INT, "1"},
+		{token.IDENT, "x"},
+		{token.IDENT, "_9"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - expected=%q %q, got %q %q", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	input := `#!/usr/bin/env monkey
+// A line comment.
+let x = 5; // Trailing comment.
+/* A block
+   comment */ let y /* inline */ = x / 2;
+/* Nested /* block */ comments */ y
+// Comment at the end without a newline`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LET, "let"},
+		{token.IDENT, "x"},
+		{token.ASSIGN, "="},
+		{token.INT, "5"},
+		{token.SEMICOLON, ";"},
+		{token.LET, "let"},
+		{token.IDENT, "y"},
+		{token.ASSIGN, "="},
+		{token.IDENT, "x"},
+		{token.SLASH, "/"},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "y"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - expected=%q %q, got %q %q", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+
+	if len(l.Errors()) != 0 {
+		t.Fatalf("unexpected errors %q", l.Errors())
+	}
+}
+
+func TestShebangOnlyOnFirstLine(t *testing.T) {
+	l := New("x\n#!y")
+
+	expected := []token.TokenType{token.IDENT, token.ILLEGAL, token.BANG, token.IDENT, token.EOF}
+	for i, tt := range expected {
+		tok := l.NextToken()
+		if tok.Type != tt {
+			t.Fatalf("tests[%d] - tokentype wrong expected=%q, got %q", i, tt, tok.Type)
+		}
+	}
+}
+
+func TestUnterminatedBlockComment(t *testing.T) {
+	l := New("x /* a /* b */ c")
+
+	if tok := l.NextToken(); tok.Type != token.IDENT {
+		t.Fatalf("tokentype wrong expected=%q, got %q", token.IDENT, tok.Type)
+	}
+	if tok := l.NextToken(); tok.Type != token.EOF {
+		t.Fatalf("tokentype wrong expected=%q, got %q", token.EOF, tok.Type)
+	}
+
+	expected := []string{"1:3: unterminated block comment"}
+	if len(l.Errors()) != 1 || l.Errors()[0].Error() != expected[0] {
+		t.Fatalf("errors wrong expected=%q, got %q", expected, l.Errors())
+	}
+}
+
+func TestNumbers(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{"0", token.INT, "0"},
+		{"1234567890", token.INT, "1234567890"},
+		{"1_000_000", token.INT, "1_000_000"},
+		{"0x1F", token.INT, "0x1F"},
+		{"0XdEaD_bEeF", token.INT, "0XdEaD_bEeF"},
+		{"0b1010", token.INT, "0b1010"},
+		{"0B1_0", token.INT, "0B1_0"},
+		{"0o777", token.INT, "0o777"},
+		{"0O1_7", token.INT, "0O1_7"},
+		{"1.5", token.FLOAT, "1.5"},
+		{"0.25", token.FLOAT, "0.25"},
+		{"1_000.000_1", token.FLOAT, "1_000.000_1"},
+		{"1e10", token.FLOAT, "1e10"},
+		{"1E+10", token.FLOAT, "1E+10"},
+		{"2.5e-3", token.FLOAT, "2.5e-3"},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Errorf("tests[%d] - expected=%q %q, got %q %q", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+		if len(l.Errors()) != 0 {
+			t.Errorf("tests[%d] - unexpected errors %q", i, l.Errors())
+		}
+		if tok := l.NextToken(); tok.Type != token.EOF {
+			t.Errorf("tests[%d] - expected EOF, got %q %q", i, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestNumbersNextToOtherTokens(t *testing.T) {
+	input := "1.x 2. 3..4 5-6"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.INT, "1"},
+		{token.ILLEGAL, "."},
+		{token.IDENT, "x"},
+		{token.INT, "2"},
+		{token.ILLEGAL, "."},
+		{token.INT, "3"},
+		{token.ILLEGAL, "."},
+		{token.ILLEGAL, "."},
+		{token.INT, "4"},
+		{token.INT, "5"},
+		{token.MINUS, "-"},
+		{token.INT, "6"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - expected=%q %q, got %q %q", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestMalformedNumbers(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedLiteral string
+		expectedError   string
+	}{
+		{"1__0", "1__0", "1:1: '_' must separate successive digits"},
+		{"10_", "10_", "1:1: '_' must separate successive digits"},
+		{"0x_1", "0x_1", "1:1: '_' must separate successive digits"},
+		{"0xG", "0x", "1:1: hexadecimal literal has no digits"},
+		{"0b", "0b", "1:1: binary literal has no digits"},
+		{"0b102", "0b102", "1:1: invalid digit '2' in binary literal"},
+		{"0o78", "0o78", "1:1: invalid digit '8' in octal literal"},
+		{"1e", "1e", "1:1: exponent has no digits"},
+		{"1.5e+", "1.5e+", "1:1: exponent has no digits"},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != token.ILLEGAL || tok.Literal != tt.expectedLiteral {
+			t.Errorf("tests[%d] - expected=%q %q, got %q %q", i, token.ILLEGAL, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+		if len(l.Errors()) != 1 || l.Errors()[0].Error() != tt.expectedError {
+			t.Errorf("tests[%d] - errors wrong expected=%q, got %q", i, tt.expectedError, l.Errors())
+		}
+	}
+}
+
+func TestOperators(t *testing.T) {
+	input := `<= >= && || % ** & | ^ << >> += -= *= /= < > = ! * / - +`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LTE, "<="},
+		{token.GTE, ">="},
+		{token.AND, "&&"},
+		{token.OR, "||"},
+		{token.PERCENT, "%"},
+		{token.POWER, "**"},
+		{token.AMPERSAND, "&"},
+		{token.PIPE, "|"},
+		{token.CARET, "^"},
+		{token.SHL, "<<"},
+		{token.SHR, ">>"},
+		{token.PLUS_ASSIGN, "+="},
+		{token.MINUS_ASSIGN, "-="},
+		{token.ASTERISK_ASSIGN, "*="},
+		{token.SLASH_ASSIGN, "/="},
+		{token.LT, "<"},
+		{token.GT, ">"},
+		{token.ASSIGN, "="},
+		{token.BANG, "!"},
+		{token.ASTERISK, "*"},
+		{token.SLASH, "/"},
+		{token.MINUS, "-"},
+		{token.PLUS, "+"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - expected=%q %q, got %q %q", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Error
+	}{
+		{"x @", Error{token.Position{Offset: 2, Line: 1, Column: 3}, "@", "unexpected character '@'"}},
+		{"\n  $", Error{token.Position{Offset: 3, Line: 2, Column: 3}, "$", "unexpected character '$'"}},
+		{"🍮", Error{token.Position{Offset: 0, Line: 1, Column: 1}, "🍮", "unexpected character '🍮'"}},
+		{"\xff", Error{token.Position{Offset: 0, Line: 1, Column: 1}, "\xff", "invalid UTF-8 encoding"}},
+		{`"abc`, Error{token.Position{Offset: 0, Line: 1, Column: 1}, `"abc`, "unterminated string"}},
+		{`"a\qb"`, Error{token.Position{Offset: 2, Line: 1, Column: 3}, `\q`, `unknown escape sequence \q`}},
+		{`"\u{12x}"`, Error{token.Position{Offset: 1, Line: 1, Column: 2}, `\u{12`, `invalid unicode escape, expected \u{...}`}},
+		{`"\u{D800}"`, Error{token.Position{Offset: 1, Line: 1, Column: 2}, `\u{D800}`, "invalid unicode code point U+D800"}},
+		{"a /* b", Error{token.Position{Offset: 2, Line: 1, Column: 3}, "/*", "unterminated block comment"}},
+		{"0b12", Error{token.Position{Offset: 0, Line: 1, Column: 1}, "0b12", "invalid digit '2' in binary literal"}},
+		{"1 \x00 2", Error{token.Position{Offset: 2, Line: 1, Column: 3}, "\x00", `unexpected character '\x00'`}},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		}
+
+		if len(l.Errors()) != 1 {
+			t.Fatalf("tests[%d] - expected 1 error, got %q", i, l.Errors())
+		}
+		if *l.Errors()[0] != tt.expected {
+			t.Errorf("tests[%d] - error wrong expected=%+v, got %+v", i, tt.expected, *l.Errors()[0])
+		}
+	}
+}
+
+func TestErrorString(t *testing.T) {
+	err := &Error{
+		Pos:    token.Position{Filename: "a.mk", Offset: 2, Line: 1, Column: 3},
+		Text:   "@",
+		Reason: "unexpected character '@'",
+	}
+
+	expected := "a.mk:1:3: unexpected character '@'"
+	if err.Error() != expected {
+		t.Errorf("Error() wrong expected=%q, got %q", expected, err.Error())
+	}
+}
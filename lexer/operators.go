@@ -0,0 +1,114 @@
+package lexer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kevinglasson/monkey/token"
+)
+
+// operator is an operator declared with DeclareOperator.
+type operator struct {
+	spelling  string
+	tokenType token.TokenType
+}
+
+// DeclareOperator declares an operator spelled as spelling, which is lexed as
+// a token of type t. Declared operators take precedence over the built in ones
+// and the longest spelling wins, so declaring "|>" lexes a|>b as a, |>, b
+// rather than a, |, >, b.
+//
+// The spelling is either made up of symbols, e.g. "|>" or "<=>", or is a word
+// such as "div" which is only matched as a whole identifier. DeclareOperator
+// panics if the spelling is neither. Operators must be declared before the
+// first token is read, i.e. before the Lexer is passed to the parser.
+func (l *Lexer) DeclareOperator(spelling string, t token.TokenType) {
+	switch {
+	case isWord(spelling):
+		if l.words == nil {
+			l.words = make(map[string]token.TokenType)
+		}
+		l.words[spelling] = t
+	case isSymbols(spelling):
+		for i, op := range l.operators {
+			if op.spelling == spelling {
+				l.operators[i].tokenType = t
+				return
+			}
+		}
+		l.operators = append(l.operators, operator{spelling: spelling, tokenType: t})
+		// Try the longest spellings first.
+		sort.SliceStable(l.operators, func(i, j int) bool {
+			return len(l.operators[i].spelling) > len(l.operators[j].spelling)
+		})
+	default:
+		panic(fmt.Sprintf("lexer: invalid operator spelling %q", spelling))
+	}
+}
+
+// isWord reports whether s is spelled like an identifier.
+func isWord(s string) bool {
+	for i, r := range s {
+		if !isLetter(r) && (i == 0 || !isIdentifierPart(r)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isSymbols reports whether s is made up of symbols that do not start an
+// identifier, number, string or comment and are not whitespace.
+func isSymbols(s string) bool {
+	if s == "" || !utf8.ValidString(s) {
+		return false
+	}
+	if strings.HasPrefix(s, "//") || strings.HasPrefix(s, "/*") {
+		return false
+	}
+	for _, r := range s {
+		if isLetter(r) || isDigit(r) || unicode.IsSpace(r) || r == '"' || r == '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// readOperator reads the longest declared symbol operator starting at the
+// current char, if there is one. The last char of the operator is left as the
+// current char.
+func (l *Lexer) readOperator() (token.Token, bool) {
+	for _, op := range l.operators {
+		if !l.startsWith(op.spelling) {
+			continue
+		}
+
+		for i := utf8.RuneCountInString(op.spelling); i > 1; i-- {
+			l.readChar()
+		}
+		return token.Token{Type: op.tokenType, Literal: op.spelling}, true
+	}
+
+	return token.Token{}, false
+}
+
+// startsWith reports whether the input from the current char starts with s.
+func (l *Lexer) startsWith(s string) bool {
+	if l.atEOF {
+		return false
+	}
+
+	ch := string(l.raw[:l.rawLen])
+	if !strings.HasPrefix(s, ch) {
+		return false
+	}
+
+	rest := s[len(ch):]
+	if rest == "" {
+		return true
+	}
+	b, _ := l.r.Peek(len(rest))
+	return string(b) == rest
+}
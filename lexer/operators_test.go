@@ -0,0 +1,125 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/kevinglasson/monkey/token"
+)
+
+const (
+	PIPE_FORWARD = "|>"
+	SPACESHIP    = "<=>"
+	BANG_BANG    = "!!"
+	DIV          = "DIV"
+)
+
+func declareOperators(l *Lexer) {
+	l.DeclareOperator("|>", PIPE_FORWARD)
+	l.DeclareOperator("<=>", SPACESHIP)
+	l.DeclareOperator("!!", BANG_BANG)
+	l.DeclareOperator("div", DIV)
+}
+
+func TestDeclaredOperators(t *testing.T) {
+	input := `a |> f <=> b div 2 | c <= d divide x!! !y|>`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "a"},
+		{PIPE_FORWARD, "|>"},
+		{token.IDENT, "f"},
+		{SPACESHIP, "<=>"},
+		{token.IDENT, "b"},
+		{DIV, "div"},
+		{token.INT, "2"},
+		{token.PIPE, "|"},
+		{token.IDENT, "c"},
+		{token.LTE, "<="},
+		{token.IDENT, "d"},
+		{token.IDENT, "divide"},
+		{token.IDENT, "x"},
+		{BANG_BANG, "!!"},
+		{token.BANG, "!"},
+		{token.IDENT, "y"},
+		{PIPE_FORWARD, "|>"},
+		{token.EOF, ""},
+	}
+
+	for name, l := range map[string]*Lexer{
+		"string":   New(input),
+		"one byte": NewReader(iotest.OneByteReader(strings.NewReader(input))),
+	} {
+		declareOperators(l)
+
+		for i, tt := range tests {
+			tok := l.NextToken()
+
+			if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+				t.Fatalf("%s: tests[%d] - token wrong. expected=%s %q, got=%s %q",
+					name, i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+			}
+		}
+		if len(l.Errors()) != 0 {
+			t.Errorf("%s: unexpected errors %q", name, l.Errors())
+		}
+	}
+}
+
+func TestDeclaredOperatorPositions(t *testing.T) {
+	l := New("a<=>b")
+	declareOperators(l)
+
+	expected := []struct {
+		offset, end int
+	}{
+		{0, 1},
+		{1, 4},
+		{4, 5},
+	}
+
+	for i, tt := range expected {
+		tok := l.NextToken()
+		if tok.Pos.Offset != tt.offset || tok.End.Offset != tt.end {
+			t.Errorf("tests[%d] - %q span wrong. expected=%d-%d, got=%d-%d",
+				i, tok.Literal, tt.offset, tt.end, tok.Pos.Offset, tok.End.Offset)
+		}
+	}
+}
+
+func TestDeclaredOperatorsRoundTrip(t *testing.T) {
+	input := "a |> /* c */ f<=>b div\n!!x"
+
+	l := New(input)
+	declareOperators(l)
+	l.KeepTrivia()
+
+	var out strings.Builder
+	for {
+		tok := l.NextToken()
+		out.WriteString(tok.Source())
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+
+	if out.String() != input {
+		t.Errorf("round trip wrong.\nexpected=%q\ngot=     %q", input, out.String())
+	}
+}
+
+func TestInvalidOperatorSpelling(t *testing.T) {
+	for _, spelling := range []string{"", "a+", "1x", "< >", `"`, "//", "/*", "\xff"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("DeclareOperator(%q) did not panic", spelling)
+				}
+			}()
+			New("").DeclareOperator(spelling, "OP")
+		}()
+	}
+}
@@ -0,0 +1,102 @@
+package lexer
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/kevinglasson/monkey/token"
+)
+
+func TestReaderMatchesString(t *testing.T) {
+	input := `#!/usr/bin/env monkey
+/* A /* nested */ comment */
+let café = "crème brûlée 🍮\t\u{1F600}";
+let add = fn(x, y) { x + y }; // Adds.
+let n = 0x1F + 1_000 * 2.5e-3 ** 2;
+if (n >= 10 && n != 11 || !true) { n <<= 1 } else { n %= 3 }
+"unterminated ` + "\xff"
+
+	// Make the input bigger than the buffer so it has to be refilled, with
+	// multi-byte chars straddling the refills.
+	input = strings.Repeat(input+"\n", 2*bufferSize/len(input)+1)
+
+	readers := map[string]func() io.Reader{
+		"whole":    func() io.Reader { return strings.NewReader(input) },
+		"one byte": func() io.Reader { return iotest.OneByteReader(strings.NewReader(input)) },
+		"half":     func() io.Reader { return iotest.HalfReader(strings.NewReader(input)) },
+	}
+
+	expected := New(input)
+	var expectedTokens []token.Token
+	for tok := expected.NextToken(); ; tok = expected.NextToken() {
+		expectedTokens = append(expectedTokens, tok)
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+
+	for name, newReader := range readers {
+		l := NewReader(newReader())
+
+		for i, want := range expectedTokens {
+			got := l.NextToken()
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("%s: tokens[%d] - expected=%+v, got %+v", name, i, want, got)
+			}
+		}
+
+		if !reflect.DeepEqual(l.Errors(), expected.Errors()) {
+			t.Fatalf("%s: errors wrong expected=%q, got %q", name, expected.Errors(), l.Errors())
+		}
+	}
+}
+
+// failingReader returns it's input and then err instead of io.EOF.
+type failingReader struct {
+	input io.Reader
+	err   error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	n, err := r.input.Read(p)
+	if err == io.EOF {
+		return n, r.err
+	}
+	return n, err
+}
+
+func TestReaderError(t *testing.T) {
+	r := &failingReader{input: strings.NewReader("let x = 5;\nx"), err: errors.New("disk on fire")}
+	l := NewFileReader("x.mk", r)
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LET, "let"},
+		{token.IDENT, "x"},
+		{token.ASSIGN, "="},
+		{token.INT, "5"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.EOF, ""},
+		{token.EOF, ""},
+	}
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - expected=%q %q, got %q %q", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+
+	expected := []string{"x.mk:2:2: read error: disk on fire"}
+	if len(l.Errors()) != 1 || l.Errors()[0].Error() != expected[0] {
+		t.Fatalf("errors wrong expected=%q, got %q", expected, l.Errors())
+	}
+}
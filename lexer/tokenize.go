@@ -0,0 +1,103 @@
+package lexer
+
+import (
+	"context"
+
+	"github.com/kevinglasson/monkey/token"
+)
+
+// Tokenize lexes all of src and returns the tokens, ending with the EOF token.
+// If there were problems with src the tokens are still returned, along with
+// an ErrorList of the problems.
+func Tokenize(src string) ([]token.Token, error) {
+	l := New(src)
+
+	var tokens []token.Token
+	for it := l.Iter(); it.Next(); {
+		tokens = append(tokens, it.Token())
+	}
+
+	return tokens, ErrorList(l.Errors()).Err()
+}
+
+// Iterator pulls tokens from a Lexer one at a time:
+//
+//	it := l.Iter()
+//	for it.Next() {
+//		tok := it.Token()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator struct {
+	l   *Lexer
+	tok token.Token
+	// done is set once the EOF token has been returned.
+	done bool
+}
+
+// Iter returns an Iterator over the remaining tokens of the Lexer.
+func (l *Lexer) Iter() *Iterator {
+	return &Iterator{l: l}
+}
+
+// Next advances to the next token, which is then available from Token. It
+// returns false once the EOF token has been returned.
+func (it *Iterator) Next() bool {
+	if it.done {
+		return false
+	}
+
+	it.tok = it.l.NextToken()
+	if it.tok.Type == token.EOF {
+		it.done = true
+	}
+
+	return true
+}
+
+// Token returns the token Next advanced to.
+func (it *Iterator) Token() token.Token {
+	return it.tok
+}
+
+// Err returns the problems found in the input so far as an ErrorList, or nil
+// if there weren't any.
+func (it *Iterator) Err() error {
+	return ErrorList(it.l.Errors()).Err()
+}
+
+// streamBuffer is how many tokens Stream lexes ahead of the receiver.
+const streamBuffer = 64
+
+// Stream lexes on it's own goroutine, sending the tokens on the returned
+// channel up to and including the EOF token, so that lexing can run alongside
+// whatever consumes the tokens. The channel is closed when the tokens run out
+// or ctx is done, whichever is first. The Lexer must not be used until the
+// channel is closed, after which l.Errors() holds the problems found.
+func Stream(ctx context.Context, l *Lexer) <-chan token.Token {
+	ch := make(chan token.Token, streamBuffer)
+
+	go func() {
+		defer close(ch)
+
+		// ctx is checked before lexing and sending each token as a select
+		// with both cases ready picks one at random, so on it's own it would
+		// carry on sending for as long as the receiver keeps up.
+		for it := l.Iter(); ctx.Err() == nil && it.Next(); {
+			tok := it.Token()
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case ch <- tok:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
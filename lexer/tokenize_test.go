@@ -0,0 +1,109 @@
+package lexer
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kevinglasson/monkey/token"
+)
+
+func TestTokenize(t *testing.T) {
+	tokens, err := Tokenize("let x = 5;")
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	expected := []token.TokenType{token.LET, token.IDENT, token.ASSIGN, token.INT, token.SEMICOLON, token.EOF}
+	if len(tokens) != len(expected) {
+		t.Fatalf("wrong number of tokens expected=%d, got %d", len(expected), len(tokens))
+	}
+	for i, tt := range expected {
+		if tokens[i].Type != tt {
+			t.Errorf("tokens[%d] - tokentype wrong expected=%q, got %q", i, tt, tokens[i].Type)
+		}
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	tokens, err := Tokenize("x @ \"abc")
+	if len(tokens) != 4 || tokens[3].Type != token.EOF {
+		t.Fatalf("expected 4 tokens ending in EOF, got %+v", tokens)
+	}
+
+	list, ok := err.(ErrorList)
+	if !ok {
+		t.Fatalf("err not ErrorList. got=%T", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("expected 2 errors, got %q", list)
+	}
+
+	expected := "1:3: unexpected character '@' (and 1 more errors)"
+	if err.Error() != expected {
+		t.Errorf("Error() wrong expected=%q, got %q", expected, err.Error())
+	}
+}
+
+func TestIterator(t *testing.T) {
+	it := New("a + @").Iter()
+
+	var types []token.TokenType
+	for it.Next() {
+		types = append(types, it.Token().Type)
+	}
+
+	expected := []token.TokenType{token.IDENT, token.PLUS, token.ILLEGAL, token.EOF}
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("tokens wrong expected=%q, got %q", expected, types)
+	}
+
+	if it.Next() {
+		t.Errorf("Next returned true after EOF")
+	}
+
+	if it.Err() == nil || it.Err().Error() != "1:5: unexpected character '@'" {
+		t.Errorf("Err() wrong, got %v", it.Err())
+	}
+}
+
+func TestStream(t *testing.T) {
+	input := strings.Repeat("let x = 1 + 2;\n", 100)
+
+	expected, err := Tokenize(input)
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	var got []token.Token
+	for tok := range Stream(context.Background(), New(input)) {
+		got = append(got, tok)
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("streamed tokens differ from Tokenize")
+	}
+}
+
+func TestStreamCancel(t *testing.T) {
+	// Enough tokens that the stream can't buffer them all.
+	input := strings.Repeat("x ", 10*streamBuffer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := Stream(ctx, New(input))
+
+	<-ch
+	cancel()
+
+	n := 0
+	for range ch {
+		n++
+	}
+
+	// The tokens already buffered may still be received, as may the one being
+	// sent when ctx was cancelled, but no more than that.
+	if n > streamBuffer+1 {
+		t.Errorf("received %d tokens after cancel", n)
+	}
+}
@@ -0,0 +1,119 @@
+package lexer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/kevinglasson/monkey/token"
+)
+
+func TestTriviaRoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"   ",
+		"let x = 5;",
+		"#!/usr/bin/env monkey\nlet x = 5; // five\n\n/* a /* nested */ block */\nx\n",
+		"let s = \"tab\\t \\u{1F600} \\\"quoted\\\"\";\r\nlet t = s +\r\n  \"!\";\r\n",
+		"if (a <= b && c) {\n\treturn a ** 2\n} else { b }  \n",
+		"let café = \"crème\"; \xff @ 0x 1__0 \"unterminated",
+		"x /* unterminated block comment",
+		"x // comment at the end",
+		"\x000",
+		"a // c\x00d\n/* e\x00f */ \"g\x00h\" \x00",
+		"let s = \"a ${ b /* c */ } d ${ fn() { \"${e}\" }() }\";",
+		"\"unterminated ${ x ",
+		"\r \r\n\n\t",
+	}
+
+	for _, input := range inputs {
+		for name, l := range map[string]*Lexer{
+			"string":   New(input),
+			"one byte": NewReader(iotest.OneByteReader(strings.NewReader(input))),
+		} {
+			l.KeepTrivia()
+
+			var out strings.Builder
+			for {
+				tok := l.NextToken()
+				out.WriteString(tok.Source())
+				if tok.Type == token.EOF {
+					break
+				}
+			}
+
+			if out.String() != input {
+				t.Errorf("%s: round trip wrong.\nexpected=%q\ngot=     %q", name, input, out.String())
+			}
+		}
+	}
+}
+
+func TestTriviaAttachment(t *testing.T) {
+	input := "#!monkey\n// Add.\nlet  x = \"a\\n\"; /* b */ // c\n\n  x\n"
+
+	tests := []struct {
+		expectedType     token.TokenType
+		expectedRaw      string
+		expectedLeading  []token.Trivia
+		expectedTrailing []token.Trivia
+	}{
+		{token.LET, "let",
+			[]token.Trivia{
+				{Kind: token.SHEBANG, Text: "#!monkey"},
+				{Kind: token.NEWLINE, Text: "\n"},
+				{Kind: token.LINE_COMMENT, Text: "// Add."},
+				{Kind: token.NEWLINE, Text: "\n"},
+			},
+			[]token.Trivia{{Kind: token.WHITESPACE, Text: "  "}},
+		},
+		{token.IDENT, "x", nil, []token.Trivia{{Kind: token.WHITESPACE, Text: " "}}},
+		{token.ASSIGN, "=", nil, []token.Trivia{{Kind: token.WHITESPACE, Text: " "}}},
+		{token.STRING, `"a\n"`, nil, nil},
+		{token.SEMICOLON, ";", nil,
+			[]token.Trivia{
+				{Kind: token.WHITESPACE, Text: " "},
+				{Kind: token.BLOCK_COMMENT, Text: "/* b */"},
+				{Kind: token.WHITESPACE, Text: " "},
+				{Kind: token.LINE_COMMENT, Text: "// c"},
+				{Kind: token.NEWLINE, Text: "\n"},
+			},
+		},
+		{token.IDENT, "x",
+			[]token.Trivia{
+				{Kind: token.NEWLINE, Text: "\n"},
+				{Kind: token.WHITESPACE, Text: "  "},
+			},
+			[]token.Trivia{{Kind: token.NEWLINE, Text: "\n"}},
+		},
+		{token.EOF, "", nil, nil},
+	}
+
+	l := New(input)
+	l.KeepTrivia()
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Raw != tt.expectedRaw {
+			t.Fatalf("tests[%d] - expected=%q %q, got %q %q", i, tt.expectedType, tt.expectedRaw, tok.Type, tok.Raw)
+		}
+		if !reflect.DeepEqual(tok.Leading, tt.expectedLeading) {
+			t.Errorf("tests[%d] - leading trivia wrong expected=%q, got %q", i, tt.expectedLeading, tok.Leading)
+		}
+		if !reflect.DeepEqual(tok.Trailing, tt.expectedTrailing) {
+			t.Errorf("tests[%d] - trailing trivia wrong expected=%q, got %q", i, tt.expectedTrailing, tok.Trailing)
+		}
+	}
+}
+
+func TestNoTriviaByDefault(t *testing.T) {
+	l := New("  let x = \"a\" // comment\n")
+
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		if tok.Raw != "" || tok.Leading != nil || tok.Trailing != nil {
+			t.Fatalf("token %q has trivia: %+v", tok.Literal, tok)
+		}
+	}
+}
@@ -1,20 +1,108 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/user"
 
+	"github.com/kevinglasson/monkey/evaluator"
+	"github.com/kevinglasson/monkey/lexer"
+	"github.com/kevinglasson/monkey/object"
+	"github.com/kevinglasson/monkey/parser"
 	"github.com/kevinglasson/monkey/repl"
 )
 
 func main() {
-	user, err := user.Current()
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run is the entry point of the monkey binary. With no arguments it starts the
+// REPL, otherwise it executes the source given by -e or streams the file named
+// by the first argument ("-" for stdin). It returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("monkey", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: monkey [-e code | file]\n")
+		flags.PrintDefaults()
+	}
+	expr := flags.String("e", "", "execute `code` instead of a file")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	// An empty -e is still code to run, so check if it was given rather than
+	// for a value.
+	exprSet := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "e" {
+			exprSet = true
+		}
+	})
 
-	if err != nil {
-		panic(err)
+	var l *lexer.Lexer
+	switch {
+	case exprSet:
+		if flags.NArg() != 0 {
+			flags.Usage()
+			return 2
+		}
+		l = lexer.New(*expr)
+	case flags.NArg() == 1:
+		name := flags.Arg(0)
+		if name == "-" {
+			l = lexer.NewReader(stdin)
+			break
+		}
+		f, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintf(stderr, "monkey: %s\n", err)
+			return 1
+		}
+		defer f.Close()
+		l = lexer.NewFileReader(name, f)
+	case flags.NArg() == 0:
+		user, err := user.Current()
+		if err != nil {
+			panic(err)
+		}
+		fmt.Fprintf(stdout, "Hello %s! This is the Monkey programming languange REPL!\n", user.Username)
+		fmt.Fprintf(stdout, "Feel free to type in commands\n")
+		repl.Start(stdin, stdout)
+		return 0
+	default:
+		flags.Usage()
+		return 2
 	}
-	fmt.Printf("Hello %s! This is the Monkey programming languange REPL!\n", user.Username)
-	fmt.Printf("Feel free to type in commands\n")
-	repl.Start(os.Stdin, os.Stdout)
+
+	return execute(l, stdout, stderr)
+}
+
+// execute parses and evaluates the source read by l. The result is written to
+// stdout, errors are written to stderr and cause a non-zero exit status.
+func execute(l *lexer.Lexer, stdout, stderr io.Writer) int {
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		for _, msg := range p.Errors() {
+			fmt.Fprintf(stderr, "parse error: %s\n", msg)
+		}
+		return 1
+	}
+
+	evaluated := evaluator.Eval(program, object.NewEnvironment())
+	if evaluated == nil {
+		return 0
+	}
+	if errObj, ok := evaluated.(*object.Error); ok {
+		fmt.Fprintf(stderr, "runtime error: %s\n", errObj.Message)
+		return 1
+	}
+
+	fmt.Fprintln(stdout, evaluated.Inspect())
+	return 0
 }
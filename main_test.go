@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "monkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	script := filepath.Join(dir, "script.mk")
+	err = ioutil.WriteFile(script, []byte("let add = fn(a, b) { a + b };\nadd(2, 3)\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args           []string
+		stdin          string
+		expectedStatus int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{[]string{"-e", "1 + 2"}, "", 0, "3\n", ""},
+		{[]string{"-e", "let x = 1;"}, "", 0, "", ""},
+		{[]string{"-e", ""}, "", 0, "", ""},
+		{[]string{script}, "", 0, "5\n", ""},
+		{[]string{"-"}, "2 * 21", 0, "42\n", ""},
+		{[]string{"-e", "let = 1"}, "", 1, "", "parse error: 1:5: expected next token to be IDENT, got =\n"},
+		{[]string{"-e", "1 + true"}, "", 1, "", "runtime error: type mismatch: INTEGER + BOOLEAN\n"},
+		{[]string{"-e", "1", script}, "", 2, "", "usage: monkey [-e code | file]\n"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+
+		if status != tt.expectedStatus {
+			t.Errorf("%q: wrong status. expected=%d, got=%d", tt.args, tt.expectedStatus, status)
+		}
+		if stdout.String() != tt.expectedStdout {
+			t.Errorf("%q: wrong stdout. expected=%q, got=%q", tt.args, tt.expectedStdout, stdout.String())
+		}
+		if !strings.HasPrefix(stderr.String(), tt.expectedStderr) {
+			t.Errorf("%q: wrong stderr. expected=%q, got=%q", tt.args, tt.expectedStderr, stderr.String())
+		}
+	}
+}
+
+func TestRunMissingFile(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"does-not-exist.mk"}, strings.NewReader(""), &stdout, &stderr)
+
+	if status != 1 {
+		t.Errorf("wrong status. expected=1, got=%d", status)
+	}
+	if !strings.HasPrefix(stderr.String(), "monkey: open does-not-exist.mk") {
+		t.Errorf("wrong stderr. got=%q", stderr.String())
+	}
+}
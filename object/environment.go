@@ -0,0 +1,54 @@
+package object
+
+// Environment holds the values bound to identifiers. Lookups that miss fall
+// through to the outer Environment, which is how nested scopes and closures
+// see the bindings around them.
+type Environment struct {
+	store map[string]Object
+	outer *Environment
+}
+
+// NewEnvironment creates a new empty Environment.
+func NewEnvironment() *Environment {
+	s := make(map[string]Object)
+	return &Environment{store: s, outer: nil}
+}
+
+// NewEnclosedEnvironment creates a new empty Environment enclosed by outer.
+func NewEnclosedEnvironment(outer *Environment) *Environment {
+	env := NewEnvironment()
+	env.outer = outer
+	return env
+}
+
+// Get returns the value bound to name, searching the enclosing Environments
+// if it isn't bound in this one.
+func (e *Environment) Get(name string) (Object, bool) {
+	obj, ok := e.store[name]
+	if !ok && e.outer != nil {
+		obj, ok = e.outer.Get(name)
+	}
+	return obj, ok
+}
+
+// Set binds val to name in this Environment and returns val. It never modifies
+// an enclosing Environment, so a binding in an inner scope shadows the outer
+// one.
+func (e *Environment) Set(name string, val Object) Object {
+	e.store[name] = val
+	return val
+}
+
+// Assign rebinds name to val in the nearest Environment it is bound in,
+// searching the enclosing Environments if it isn't bound in this one. It
+// reports whether name was found.
+func (e *Environment) Assign(name string, val Object) bool {
+	if _, ok := e.store[name]; ok {
+		e.store[name] = val
+		return true
+	}
+	if e.outer != nil {
+		return e.outer.Assign(name, val)
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package object
+
+import "testing"
+
+func TestEnclosedEnvironment(t *testing.T) {
+	outer := NewEnvironment()
+	outer.Set("a", &Integer{Value: 1})
+	outer.Set("b", &Integer{Value: 2})
+
+	inner := NewEnclosedEnvironment(outer)
+	inner.Set("b", &Integer{Value: 3})
+
+	tests := []struct {
+		env      *Environment
+		name     string
+		expected int64
+	}{
+		{inner, "a", 1},
+		{inner, "b", 3},
+		{outer, "b", 2},
+	}
+
+	for _, tt := range tests {
+		obj, ok := tt.env.Get(tt.name)
+		if !ok {
+			t.Fatalf("%s not found", tt.name)
+		}
+		if obj.(*Integer).Value != tt.expected {
+			t.Errorf("%s has wrong value. got=%d, want=%d", tt.name, obj.(*Integer).Value, tt.expected)
+		}
+	}
+
+	if _, ok := outer.Get("c"); ok {
+		t.Errorf("c should not be found")
+	}
+}
@@ -0,0 +1,77 @@
+package object
+
+import (
+	"bytes"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// HashKey identifies the value of a Hashable object, two objects with equal
+// values have the same HashKey.
+type HashKey struct {
+	Type  ObjectType
+	Value uint64
+}
+
+// Hashable is an Object that can be used as a key in a Hash.
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+// HashKey implements Hashable for Integer.
+func (i *Integer) HashKey() HashKey {
+	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
+}
+
+// HashKey implements Hashable for Boolean.
+func (b *Boolean) HashKey() HashKey {
+	var value uint64
+	if b.Value {
+		value = 1
+	}
+
+	return HashKey{Type: b.Type(), Value: value}
+}
+
+// HashKey implements Hashable for String.
+func (s *String) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(s.Value))
+
+	return HashKey{Type: s.Type(), Value: h.Sum64()}
+}
+
+// HashPair is a key and it's value in a Hash. The key is kept as the HashKey
+// loses it.
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+// Hash is a map from Hashable keys to values.
+type Hash struct {
+	Pairs map[HashKey]HashPair
+}
+
+// Type implements Object for Hash.
+func (h *Hash) Type() ObjectType { return HASH_OBJ }
+
+// Inspect implements Object for Hash. The pairs are sorted so that the same
+// Hash always looks the same.
+func (h *Hash) Inspect() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for _, pair := range h.Pairs {
+		pairs = append(pairs, pair.Key.Inspect()+": "+pair.Value.Inspect())
+	}
+	sort.Strings(pairs)
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
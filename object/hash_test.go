@@ -0,0 +1,40 @@
+package object
+
+import "testing"
+
+func TestHashKey(t *testing.T) {
+	tests := []struct {
+		a, b     Hashable
+		expected bool
+	}{
+		{&String{Value: "Hello World"}, &String{Value: "Hello World"}, true},
+		{&String{Value: "Hello World"}, &String{Value: "My name is johnny"}, false},
+		{&Integer{Value: 1}, &Integer{Value: 1}, true},
+		{&Integer{Value: 1}, &Integer{Value: 2}, false},
+		{&Boolean{Value: true}, &Boolean{Value: true}, true},
+		{&Boolean{Value: true}, &Boolean{Value: false}, false},
+		// Equal values of different types are different keys.
+		{&Integer{Value: 1}, &Boolean{Value: true}, false},
+	}
+
+	for _, tt := range tests {
+		if (tt.a.HashKey() == tt.b.HashKey()) != tt.expected {
+			t.Errorf("%s %s and %s %s: HashKeys equal expected=%t",
+				tt.a.Type(), tt.a.Inspect(), tt.b.Type(), tt.b.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestHashInspect(t *testing.T) {
+	one, two := &Integer{Value: 1}, &String{Value: "two"}
+
+	hash := &Hash{Pairs: map[HashKey]HashPair{
+		two.HashKey(): {Key: two, Value: &Boolean{Value: true}},
+		one.HashKey(): {Key: one, Value: one},
+	}}
+
+	expected := "{1: 1, two: true}"
+	if hash.Inspect() != expected {
+		t.Errorf("wrong Inspect. expected=%q, got=%q", expected, hash.Inspect())
+	}
+}
@@ -0,0 +1,180 @@
+package object
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kevinglasson/monkey/ast"
+)
+
+// ObjectType is a constant representing the type of an Object.
+type ObjectType string
+
+const (
+	// INTEGER_OBJ is the ObjectType for integers.
+	INTEGER_OBJ = "INTEGER"
+	// FLOAT_OBJ is the ObjectType for floating point numbers.
+	FLOAT_OBJ = "FLOAT"
+	// STRING_OBJ is the ObjectType for strings.
+	STRING_OBJ = "STRING"
+	// BOOLEAN_OBJ is the ObjectType for booleans.
+	BOOLEAN_OBJ = "BOOLEAN"
+	// NULL_OBJ is the ObjectType for the absence of a value.
+	NULL_OBJ = "NULL"
+	// RETURN_VALUE_OBJ is the ObjectType wrapping a returned value.
+	RETURN_VALUE_OBJ = "RETURN_VALUE"
+	// ERROR_OBJ is the ObjectType for runtime errors.
+	ERROR_OBJ = "ERROR"
+	// FUNCTION_OBJ is the ObjectType for functions.
+	FUNCTION_OBJ = "FUNCTION"
+	// ARRAY_OBJ is the ObjectType for arrays.
+	ARRAY_OBJ = "ARRAY"
+	// HASH_OBJ is the ObjectType for hash maps.
+	HASH_OBJ = "HASH"
+)
+
+// Object is every value produced while evaluating a program.
+type Object interface {
+	Type() ObjectType
+	Inspect() string
+}
+
+// Integer wraps an int64 value.
+type Integer struct {
+	Value int64
+}
+
+// Type implements Object for Integer.
+func (i *Integer) Type() ObjectType { return INTEGER_OBJ }
+
+// Inspect implements Object for Integer.
+func (i *Integer) Inspect() string { return fmt.Sprintf("%d", i.Value) }
+
+// Float wraps a float64 value.
+type Float struct {
+	Value float64
+}
+
+// Type implements Object for Float.
+func (f *Float) Type() ObjectType { return FLOAT_OBJ }
+
+// Inspect implements Object for Float. The output always looks like a float,
+// so 2.0 is "2.0" rather than "2".
+func (f *Float) Inspect() string {
+	s := strconv.FormatFloat(f.Value, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eIN") {
+		s += ".0"
+	}
+	return s
+}
+
+// String wraps a string value.
+type String struct {
+	Value string
+}
+
+// Type implements Object for String.
+func (s *String) Type() ObjectType { return STRING_OBJ }
+
+// Inspect implements Object for String.
+func (s *String) Inspect() string { return s.Value }
+
+// Boolean wraps a bool value.
+type Boolean struct {
+	Value bool
+}
+
+// Type implements Object for Boolean.
+func (b *Boolean) Type() ObjectType { return BOOLEAN_OBJ }
+
+// Inspect implements Object for Boolean.
+func (b *Boolean) Inspect() string { return fmt.Sprintf("%t", b.Value) }
+
+// Null represents the absence of a value.
+type Null struct{}
+
+// Type implements Object for Null.
+func (n *Null) Type() ObjectType { return NULL_OBJ }
+
+// Inspect implements Object for Null.
+func (n *Null) Inspect() string { return "null" }
+
+// ReturnValue wraps the value of a return statement so that it can be passed
+// up through nested blocks until it reaches the function or program.
+type ReturnValue struct {
+	Value Object
+}
+
+// Type implements Object for ReturnValue.
+func (rv *ReturnValue) Type() ObjectType { return RETURN_VALUE_OBJ }
+
+// Inspect implements Object for ReturnValue.
+func (rv *ReturnValue) Inspect() string { return rv.Value.Inspect() }
+
+// Error is a runtime error, it stops the evaluation of the program.
+type Error struct {
+	Message string
+}
+
+// Type implements Object for Error.
+func (e *Error) Type() ObjectType { return ERROR_OBJ }
+
+// Inspect implements Object for Error.
+func (e *Error) Inspect() string { return "ERROR: " + e.Message }
+
+// Function is a function value created by evaluating a function literal. Env
+// is the Environment the literal was evaluated in, the body is evaluated in an
+// Environment enclosed by it so that free variables resolve to the bindings
+// that were visible where the function was defined.
+type Function struct {
+	Parameters []*ast.Identifier
+	Body       *ast.BlockStatement
+	Env        *Environment
+}
+
+// Type implements Object for Function.
+func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
+
+// Inspect implements Object for Function.
+func (f *Function) Inspect() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range f.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString("fn(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") {\n")
+	out.WriteString(f.Body.String())
+	out.WriteString("\n}")
+
+	return out.String()
+}
+
+// Array is an ordered list of values.
+type Array struct {
+	Elements []Object
+}
+
+// Type implements Object for Array.
+func (a *Array) Type() ObjectType { return ARRAY_OBJ }
+
+// Inspect implements Object for Array.
+func (a *Array) Inspect() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+	for _, e := range a.Elements {
+		elements = append(elements, e.Inspect())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/kevinglasson/monkey/lexer"
+	"github.com/kevinglasson/monkey/token"
+)
+
+// ErrorCode identifies the kind of a ParseError so that tools can act on it
+// without matching the message.
+type ErrorCode string
+
+const (
+	// LexError is a problem found by the lexer, e.g. an unterminated string.
+	LexError ErrorCode = "lex-error"
+	// UnexpectedToken is a token other than one of the Expected ones.
+	UnexpectedToken ErrorCode = "unexpected-token"
+	// NoPrefixParseFn is a token that cannot start an expression.
+	NoPrefixParseFn ErrorCode = "no-prefix-parse-fn"
+	// InvalidNumber is a number literal that does not fit in it's type.
+	InvalidNumber ErrorCode = "invalid-number"
+	// InvalidAssignment is an assignment to something other than an
+	// identifier.
+	InvalidAssignment ErrorCode = "invalid-assignment"
+	// EmptyInterpolation is a ${} in a string without an expression.
+	EmptyInterpolation ErrorCode = "empty-interpolation"
+	// NonAssociative is a non associative operator used next to another
+	// operator of the same precedence.
+	NonAssociative ErrorCode = "non-associative"
+	// TooManyErrors is reported once the Parser gives up, see SetMaxErrors.
+	TooManyErrors ErrorCode = "too-many-errors"
+)
+
+// DefaultMaxErrors is how many errors a Parser reports before giving up.
+const DefaultMaxErrors = 10
+
+// ParseError is a problem found in the input by the Parser.
+type ParseError struct {
+	// Start and End are the positions of the offending input.
+	Start token.Position
+	End   token.Position
+	// Code is the kind of problem.
+	Code ErrorCode
+	// Expected are the token types that would have been accepted, if any.
+	Expected []token.TokenType
+	// Actual is the token the problem was found at.
+	Actual token.Token
+	// Message explains what is wrong, e.g. "no prefix parse function for ;
+	// found".
+	Message string
+}
+
+// Error implements error, formatting the ParseError as "position: message".
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Start, e.Message)
+}
+
+// add records err unless the Parser has already found too many errors. The
+// first error over the limit is replaced with a TooManyErrors error.
+//
+// After an error the Parser is panicking until it synchronizes at the next
+// statement, any errors in the meantime are most likely caused by the first so
+// they are dropped. Errors from the lexer are always kept, the parser carries
+// on from those without getting lost.
+func (p *Parser) add(err *ParseError) {
+	if p.tooManyErrors {
+		return
+	}
+	if err.Code != LexError {
+		if p.panicking {
+			return
+		}
+		p.panicking = true
+	}
+	if p.maxErrors > 0 && len(p.errors) >= p.maxErrors {
+		p.tooManyErrors = true
+		err = &ParseError{
+			Start:   err.Start,
+			End:     err.End,
+			Code:    TooManyErrors,
+			Actual:  err.Actual,
+			Message: "too many errors",
+		}
+	}
+	p.errors = append(p.errors, err)
+}
+
+// SetMaxErrors sets how many errors are reported before the Parser gives up, n
+// of zero or less means there is no limit. It defaults to DefaultMaxErrors.
+func (p *Parser) SetMaxErrors(n int) {
+	p.maxErrors = n
+}
+
+// error records a ParseError found at tok.
+func (p *Parser) error(code ErrorCode, tok token.Token, expected []token.TokenType, format string, a ...interface{}) {
+	p.add(&ParseError{
+		Start:    tok.Pos,
+		End:      tok.End,
+		Code:     code,
+		Expected: expected,
+		Actual:   tok,
+		Message:  fmt.Sprintf(format, a...),
+	})
+}
+
+// lexError records an error found by the lexer while reading tok.
+func (p *Parser) lexError(err *lexer.Error, tok token.Token) {
+	p.add(&ParseError{
+		Start:   err.Pos,
+		End:     advance(err.Pos, err.Text),
+		Code:    LexError,
+		Actual:  tok,
+		Message: err.Reason,
+	})
+}
+
+// advance returns the position after text when it starts at pos.
+func advance(pos token.Position, text string) token.Position {
+	for len(text) > 0 {
+		r, size := utf8.DecodeRuneInString(text)
+		text = text[size:]
+
+		pos.Offset += size
+		if r == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column += size
+		}
+	}
+	return pos
+}
+
+// ParseErrors returns all of the errors the Parser has collected.
+func (p *Parser) ParseErrors() []*ParseError {
+	return p.errors
+}
+
+// Errors returns the errors the Parser has collected formatted as strings, it
+// is a convenience for when only the messages are wanted.
+func (p *Parser) Errors() []string {
+	msgs := make([]string, len(p.errors))
+	for i, err := range p.errors {
+		msgs[i] = err.Error()
+	}
+	return msgs
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"github.com/kevinglasson/monkey/ast"
+	"github.com/kevinglasson/monkey/token"
+)
+
+// Associativity is how an infix operator groups with the operators of the same
+// precedence around it.
+type Associativity int
+
+const (
+	// AssocLeft groups a - b - c as (a - b) - c.
+	AssocLeft Associativity = iota
+	// AssocRight groups a ** b ** c as a ** (b ** c).
+	AssocRight
+	// AssocNone makes a < b < c an error, the operators have to be grouped
+	// with parentheses.
+	AssocNone
+)
+
+// The Register*Operator functions add operators to the language, e.g. for a
+// DSL embedding Monkey. The operator's token must be one the lexer produces,
+// see lexer.DeclareOperator for new spellings. Registering an operator for a
+// token that already has one replaces it. Operators produce the same AST
+// nodes as the built in ones, with the token's literal as the Operator, so it's
+// up to the evaluator to give them a meaning.
+
+// RegisterPrefixOperator registers t as a prefix operator, e.g. -x. The operand
+// is parsed at the given precedence, for the built in operators this is
+// PREFIX.
+func (p *Parser) RegisterPrefixOperator(t token.TokenType, precedence int) {
+	p.registerPrefix(t, func() ast.Expression {
+		return p.parsePrefixOperator(precedence)
+	})
+}
+
+// RegisterInfixOperator registers t as an infix operator, e.g. a + b, with the
+// given precedence and associativity.
+func (p *Parser) RegisterInfixOperator(t token.TokenType, precedence int, assoc Associativity) {
+	p.precedences[t] = precedence
+	p.associativity[t] = assoc
+	p.registerInfix(t, p.parseInfixExpression)
+}
+
+// RegisterPostfixOperator registers t as a postfix operator, e.g. n!. The
+// precedence is how tightly it binds to the expression on it's left, e.g.
+// with a precedence above SUM a + b! is a + (b!).
+func (p *Parser) RegisterPostfixOperator(t token.TokenType, precedence int) {
+	p.precedences[t] = precedence
+	delete(p.associativity, t)
+	p.registerInfix(t, p.parsePostfixExpression)
+}
+
+func (p *Parser) parsePostfixExpression(left ast.Expression) ast.Expression {
+	return &ast.PostfixExpression{
+		Token:    p.curToken,
+		Left:     left,
+		Operator: p.curToken.Literal,
+	}
+}
@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/kevinglasson/monkey/lexer"
+	"github.com/kevinglasson/monkey/token"
+)
+
+// newDSLParser returns a Parser for input with some extra operators.
+func newDSLParser(input string) *Parser {
+	l := lexer.New(input)
+	l.DeclareOperator("|>", "|>")
+	l.DeclareOperator("<=>", "<=>")
+	l.DeclareOperator("::", "::")
+	l.DeclareOperator("!!", "!!")
+	l.DeclareOperator("√", "√")
+	l.DeclareOperator("div", "DIV")
+	l.DeclareOperator("not", "NOT")
+
+	p := New(l)
+	p.RegisterInfixOperator("|>", ASSIGN+5, AssocLeft)
+	p.RegisterInfixOperator("<=>", EQUALS, AssocNone)
+	p.RegisterInfixOperator("::", SUM-5, AssocRight)
+	p.RegisterInfixOperator("DIV", PRODUCT, AssocLeft)
+	p.RegisterPostfixOperator("!!", CALL+5)
+	p.RegisterPrefixOperator("√", PREFIX)
+	p.RegisterPrefixOperator("NOT", LOGICALAND)
+
+	return p
+}
+
+func TestRegisteredOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a |> f |> g", "((a |> f) |> g)"},
+		{"a + b |> f || g", "((a + b) |> (f || g))"},
+		{"x += a |> f", "(x += (a |> f))"},
+		{"a <=> b", "(a <=> b)"},
+		{"(a <=> b) == c", "((a <=> b) == c)"},
+		{"a == (b <=> c)", "(a == (b <=> c))"},
+		{"(a == b) <=> c", "((a == b) <=> c)"},
+		{"a <=> b + c", "(a <=> (b + c))"},
+		{"1 :: 2 :: xs", "(1 :: (2 :: xs))"},
+		{"1 + 2 :: xs", "((1 + 2) :: xs)"},
+		{"a div b * c", "((a div b) * c)"},
+		{"divide(a)", "divide(a)"},
+		{"-x!! + 1", "((-(x!!)) + 1)"},
+		{"f(x)!!", "(f(x)!!)"},
+		{"a[0]!!", "((a[0])!!)"},
+		{"√x + 1", "((√x) + 1)"},
+		{"not a == b && c", "((not(a == b)) && c)"},
+		{"a ** b ** c", "(a ** (b ** c))"},
+		{"a<=b", "(a <= b)"},
+	}
+
+	for _, tt := range tests {
+		p := newDSLParser(tt.input)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("%q: expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+}
+
+func TestNonAssociativeOperator(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"a <=> b <=> c", "1:9: <=> is not associative, use parentheses to group it with <=>"},
+		{"a <=> b == c", "1:9: <=> is not associative, use parentheses to group it with =="},
+		{"a == b <=> c", "1:8: <=> is not associative, use parentheses to group it with =="},
+		{"x || a <=> b == c", "1:14: <=> is not associative, use parentheses to group it with =="},
+		{"a <=> -b == c", "1:10: <=> is not associative, use parentheses to group it with =="},
+	}
+
+	for _, tt := range tests {
+		p := newDSLParser(tt.input)
+		p.ParseProgram()
+
+		errs := p.ParseErrors()
+		if len(errs) != 1 {
+			t.Fatalf("%q: expected 1 error, got=%q", tt.input, p.Errors())
+		}
+		if errs[0].Code != NonAssociative || errs[0].Error() != tt.expectedError {
+			t.Errorf("%q: wrong error. expected=%q, got=%q (%s)", tt.input, tt.expectedError, errs[0], errs[0].Code)
+		}
+	}
+}
+
+func TestNonAssociativeOperatorSharingPrecedence(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"a < b <=> c", "1:7: <=> is not associative, use parentheses to group it with <"},
+		{"a <=> b < c", "1:9: <=> is not associative, use parentheses to group it with <"},
+		{"a ^^ b <=> c", "1:8: <=> is not associative, use parentheses to group it with ^^"},
+		{"a <=> b ^^ c", "1:9: <=> is not associative, use parentheses to group it with ^^"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		l.DeclareOperator("<=>", "<=>")
+		l.DeclareOperator("^^", "^^")
+		p := New(l)
+		p.RegisterInfixOperator("<=>", LESSGREATER, AssocNone)
+		p.RegisterInfixOperator("^^", LESSGREATER, AssocRight)
+		p.ParseProgram()
+
+		errs := p.ParseErrors()
+		if len(errs) != 1 {
+			t.Fatalf("%q: expected 1 error, got=%q", tt.input, p.Errors())
+		}
+		if errs[0].Code != NonAssociative || errs[0].Error() != tt.expectedError {
+			t.Errorf("%q: wrong error. expected=%q, got=%q (%s)", tt.input, tt.expectedError, errs[0], errs[0].Code)
+		}
+	}
+}
+
+func TestRegisteredOperatorsAreLocalToTheParser(t *testing.T) {
+	newDSLParser("")
+
+	p := New(lexer.New("a :: b"))
+	p.ParseProgram()
+
+	if len(p.Errors()) == 0 {
+		t.Fatalf("expected errors for an unregistered operator, got none")
+	}
+	if _, ok := p.precedences[token.TokenType("::")]; ok {
+		t.Errorf("registered precedence leaked into a new Parser")
+	}
+}
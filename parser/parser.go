@@ -1,33 +1,98 @@
 package parser
 
 import (
-	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/kevinglasson/monkey/ast"
 	"github.com/kevinglasson/monkey/lexer"
 	"github.com/kevinglasson/monkey/token"
 )
 
+// The precedences of the operators, from loosest to tightest binding. They are
+// spaced apart so that operators registered with RegisterInfixOperator and
+// friends can be placed between them, e.g. SUM + 5.
 const (
-	_ int = iota
+	_ int = iota * 10
 	LOWEST
-	EQUALS  // == LESSGREATER // > or <
-	SUM     // +
-	PRODUCT // *
-	PREFIX  // -X or !X
-	CALL    // myFunction(X)
+	ASSIGN      // += -= *= /=
+	LOGICALOR   // ||
+	LOGICALAND  // &&
+	EQUALS      // ==
+	LESSGREATER // > or <
+	BITWISEOR   // |
+	BITWISEXOR  // ^
+	BITWISEAND  // &
+	SHIFT       // << or >>
+	SUM         // +
+	PRODUCT     // *
+	PREFIX      // -X or !X
+	POWER       // **
+	CALL        // myFunction(X)
+	INDEX       // array[index]
 )
 
+// precedences maps the built in infix operator token types to their
+// precedence.
+var precedences = map[token.TokenType]int{
+	token.PLUS_ASSIGN:     ASSIGN,
+	token.MINUS_ASSIGN:    ASSIGN,
+	token.ASTERISK_ASSIGN: ASSIGN,
+	token.SLASH_ASSIGN:    ASSIGN,
+	token.OR:              LOGICALOR,
+	token.AND:             LOGICALAND,
+	token.EQ:              EQUALS,
+	token.NEQ:             EQUALS,
+	token.LT:              LESSGREATER,
+	token.GT:              LESSGREATER,
+	token.LTE:             LESSGREATER,
+	token.GTE:             LESSGREATER,
+	token.PIPE:            BITWISEOR,
+	token.CARET:           BITWISEXOR,
+	token.AMPERSAND:       BITWISEAND,
+	token.SHL:             SHIFT,
+	token.SHR:             SHIFT,
+	token.PLUS:            SUM,
+	token.MINUS:           SUM,
+	token.SLASH:           PRODUCT,
+	token.ASTERISK:        PRODUCT,
+	token.PERCENT:         PRODUCT,
+	token.POWER:           POWER,
+	token.LPAREN:          CALL,
+	token.LBRACKET:        INDEX,
+}
+
 // Parser is a parser for the programming language.
 type Parser struct {
 	l      *lexer.Lexer
-	errors []string
+	errors []*ParseError
+	// lexErrors is how many of the lexer's errors have been collected.
+	lexErrors int
+	// maxErrors is how many errors are reported before giving up, zero or
+	// less means there is no limit.
+	maxErrors int
+	// tooManyErrors is set once more than maxErrors errors have been found.
+	tooManyErrors bool
+	// braces is how many braces are open before the current token.
+	braces int
+	// panicking is set from an error until the parser synchronizes at the
+	// start of the next statement.
+	panicking bool
+	// operator is the infix operator whose right operand is about to be
+	// parsed, see parseExpression.
+	operator token.Token
 
 	curToken  token.Token
 	peekToken token.Token
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
+
+	// precedences and associativity are for the infix and postfix operators,
+	// including the registered ones. Operators missing from associativity
+	// associate to the left.
+	precedences   map[token.TokenType]int
+	associativity map[token.TokenType]Associativity
 }
 
 type (
@@ -39,13 +104,56 @@ type (
 func New(l *lexer.Lexer) *Parser {
 	// Create a new parser.
 	p := &Parser{
-		l:      l,
-		errors: []string{},
+		l:         l,
+		errors:    []*ParseError{},
+		maxErrors: DefaultMaxErrors,
 	}
 
 	// Register parse functions
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
+	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
+	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.STRING_HEAD, p.parseInterpolatedString)
+	p.registerPrefix(token.TRUE, p.parseBoolean)
+	p.registerPrefix(token.FALSE, p.parseBoolean)
+	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
+	p.registerPrefix(token.IF, p.parseIfExpression)
+	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
+	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
+	// Blocks only follow if, else and fn, which parse them directly, so a {
+	// that starts an expression is always a hash literal.
+	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
+	p.registerPrefix(token.BANG, p.parsePrefixExpression)
+	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
+
+	p.precedences = make(map[token.TokenType]int, len(precedences))
+	for tt, precedence := range precedences {
+		p.precedences[tt] = precedence
+	}
+	p.associativity = map[token.TokenType]Associativity{
+		token.POWER: AssocRight,
+	}
+
+	p.infixParseFns = make(map[token.TokenType]infixParseFn)
+	for _, tt := range []token.TokenType{
+		token.PLUS, token.MINUS, token.SLASH, token.ASTERISK,
+		token.PERCENT, token.POWER,
+		token.EQ, token.NEQ, token.LT, token.GT, token.LTE, token.GTE,
+		token.AND, token.OR,
+		token.AMPERSAND, token.PIPE, token.CARET, token.SHL, token.SHR,
+	} {
+		p.registerInfix(tt, p.parseInfixExpression)
+	}
+	for _, tt := range []token.TokenType{
+		token.PLUS_ASSIGN, token.MINUS_ASSIGN,
+		token.ASTERISK_ASSIGN, token.SLASH_ASSIGN,
+	} {
+		p.registerInfix(tt, p.parseAssignExpression)
+	}
+	p.registerInfix(token.LPAREN, p.parseCallExpression)
+	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
 
 	// Initialise curToken and peekToken by reading two tokens.
 	p.nextToken()
@@ -54,11 +162,6 @@ func New(l *lexer.Lexer) *Parser {
 	return p
 }
 
-// Errors returns all of the errors the Parser has collected.
-func (p *Parser) Errors() []string {
-	return p.errors
-}
-
 // ParseProgram parses the program.
 func (p *Parser) ParseProgram() *ast.Program {
 	program := &ast.Program{}
@@ -66,11 +169,20 @@ func (p *Parser) ParseProgram() *ast.Program {
 	program.Statements = []ast.Statement{}
 
 	// Until the current token is EOF.
-	for !p.curTokenIs(token.EOF) {
+	for !p.curTokenIs(token.EOF) && !p.tooManyErrors {
+		start, braces := p.curToken, p.braces
+
 		stmt := p.parseStatement()
 		if stmt != nil {
 			program.Statements = append(program.Statements, stmt)
 		}
+
+		// Skip to the next statement after a mistake so it's only reported
+		// once.
+		if p.panicking {
+			p.synchronize(start, braces)
+			continue
+		}
 		p.nextToken()
 	}
 
@@ -80,7 +192,12 @@ func (p *Parser) ParseProgram() *ast.Program {
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
 	case token.LET:
-		return p.parseLetStatement()
+		// Return a nil Statement rather than a nil *LetStatement, which would
+		// not compare equal to nil.
+		if stmt := p.parseLetStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
 	case token.RETURN:
 		return p.parseReturnStatement()
 	default:
@@ -88,6 +205,35 @@ func (p *Parser) parseStatement() ast.Statement {
 	}
 }
 
+// synchronize skips the rest of a statement starting at start that failed to
+// parse, leaving the current token at the start of the next statement. This is
+// after a SEMICOLON, or at a LET, RETURN or the RBRACE closing the enclosing
+// block. Only tokens outside of any braces opened by the statement count, so
+// that e.g. the end of a function body is not taken for the end of the block
+// the statement is in.
+func (p *Parser) synchronize(start token.Token, braces int) {
+	p.panicking = false
+
+	// The statement may have failed at it's first token, which has to be
+	// skipped for the parser to make progress.
+	if p.curToken.Pos == start.Pos {
+		p.nextToken()
+	}
+
+	for !p.curTokenIs(token.EOF) {
+		if p.braces <= braces {
+			switch p.curToken.Type {
+			case token.RBRACE, token.LET, token.RETURN:
+				return
+			case token.SEMICOLON:
+				p.nextToken()
+				return
+			}
+		}
+		p.nextToken()
+	}
+}
+
 func (p *Parser) registerPrefix(tokenType token.TokenType, fn prefixParseFn) {
 	p.prefixParseFns[tokenType] = fn
 }
@@ -97,17 +243,39 @@ func (p *Parser) registerInfix(tokenType token.TokenType, fn infixParseFn) {
 }
 
 // peekError generates a peek error and adds it to the parsers errors slice.
+// An ILLEGAL token has already been reported by the lexer so it isn't reported
+// again, but the Parser still panics so that the rest of the statement is
+// skipped.
 func (p *Parser) peekError(t token.TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s", t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+	if p.peekTokenIs(token.ILLEGAL) {
+		p.panicking = true
+		return
+	}
+	p.error(UnexpectedToken, p.peekToken, []token.TokenType{t},
+		"expected next token to be %s, got %s", t, p.peekToken.Type)
 }
 
 // nextToken indexes the parser to the next token.
 func (p *Parser) nextToken() {
+	switch p.curToken.Type {
+	case token.LBRACE:
+		p.braces++
+	case token.RBRACE:
+		p.braces--
+	}
+
 	// The current token is not the peek token.
 	p.curToken = p.peekToken
 	// The peek token is the next token generated from the lexer.
 	p.peekToken = p.l.NextToken()
+
+	// Collect anything the lexer complained about while reading it, so that
+	// the errors are reported in the order they appear in the source.
+	errs := p.l.Errors()
+	for _, err := range errs[p.lexErrors:] {
+		p.lexError(err, p.peekToken)
+	}
+	p.lexErrors = len(errs)
 }
 
 func (p *Parser) parseLetStatement() *ast.LetStatement {
@@ -123,11 +291,15 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 
 	// We expect an ASSIGN after the LET IDENTIFIER sequence.
 	if !p.expectPeek(token.ASSIGN) {
-		p.nextToken()
+		return nil
 	}
 
-	// TODO: Skipping expressions until we get to a SEMICOLON (just for now).
-	for !p.curTokenIs(token.SEMICOLON) {
+	// Move onto the first token of the expression and parse it.
+	p.nextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+
+	// The trailing SEMICOLON is optional.
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
@@ -137,10 +309,12 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{Token: p.curToken}
 
+	// Move onto the first token of the expression and parse it.
 	p.nextToken()
+	stmt.ReturnValue = p.parseExpression(LOWEST)
 
-	// TODO: Skipping expressions until we get to a SEMICOLON (just for now).
-	for !p.curTokenIs(token.SEMICOLON) {
+	// The trailing SEMICOLON is optional.
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
@@ -178,16 +352,499 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	return stmt
 }
 
+// noPrefixParseFnError adds an error for a token that cannot start an
+// expression. An ILLEGAL token has already been reported by the lexer so it
+// isn't reported again, but the Parser still panics as in peekError.
+func (p *Parser) noPrefixParseFnError(tok token.Token) {
+	if tok.Type == token.ILLEGAL {
+		p.panicking = true
+		return
+	}
+	p.error(NoPrefixParseFn, tok, nil, "no prefix parse function for %s found", tok.Type)
+}
+
+// parseExpression parses an expression using Pratt (top down operator
+// precedence) parsing. Infix operators are folded into the left expression for
+// as long as they bind tighter than the given precedence.
 func (p *Parser) parseExpression(precedence int) ast.Expression {
+	// The last operator folded into leftExp, or the operator this is the right
+	// operand of until then. Parentheses are dropped by the time we get here
+	// so this can't be worked out from the AST.
+	last := p.operator
+	lastPrecedence := p.precedences[last.Type]
+	p.operator = token.Token{}
+
 	prefix := p.prefixParseFns[p.curToken.Type]
 	if prefix == nil {
+		p.noPrefixParseFnError(p.curToken)
 		return nil
 	}
 	leftExp := prefix()
 
+	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
+		infix := p.infixParseFns[p.peekToken.Type]
+		if infix == nil {
+			return leftExp
+		}
+
+		// A non associative operator cannot be next to another operator of
+		// the same precedence without parentheses, whichever side it is on.
+		if p.peekPrecedence() == lastPrecedence {
+			switch {
+			case p.associativity[last.Type] == AssocNone:
+				p.nonAssociativeError(last.Literal, p.peekToken.Literal)
+				return nil
+			case p.associativity[p.peekToken.Type] == AssocNone:
+				p.nonAssociativeError(p.peekToken.Literal, last.Literal)
+				return nil
+			}
+		}
+		last, lastPrecedence = p.peekToken, p.peekPrecedence()
+
+		p.nextToken()
+
+		leftExp = infix(leftExp)
+	}
+
 	return leftExp
 }
 
+// nonAssociativeError adds an error at the peek token for the non associative
+// operator op used next to other.
+func (p *Parser) nonAssociativeError(op, other string) {
+	p.error(NonAssociative, p.peekToken, nil,
+		"%s is not associative, use parentheses to group it with %s", op, other)
+}
+
+// peekPrecedence returns the precedence of the peek token.
+func (p *Parser) peekPrecedence() int {
+	if p, ok := p.precedences[p.peekToken.Type]; ok {
+		return p
+	}
+
+	return LOWEST
+}
+
+// curPrecedence returns the precedence of the current token.
+func (p *Parser) curPrecedence() int {
+	if p, ok := p.precedences[p.curToken.Type]; ok {
+		return p
+	}
+
+	return LOWEST
+}
+
 func (p *Parser) parseIdentifier() ast.Expression {
 	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 }
+
+func (p *Parser) parseIntegerLiteral() ast.Expression {
+	lit := &ast.IntegerLiteral{Token: p.curToken}
+
+	// Anything that does not fit in an int64 is an error.
+	value, err := parseInt(p.curToken.Literal)
+	if err != nil {
+		p.error(InvalidNumber, p.curToken, nil, "could not parse %q as integer", p.curToken.Literal)
+		return nil
+	}
+
+	lit.Value = value
+
+	return lit
+}
+
+// parseInt parses the literal of an INT token, which may have a base prefix
+// and digit separators. Without a prefix the literal is always decimal, even
+// with leading zeros.
+func parseInt(literal string) (int64, error) {
+	literal = strings.ReplaceAll(literal, "_", "")
+
+	base := 10
+	if len(literal) > 2 && literal[0] == '0' {
+		switch literal[1] {
+		case 'x', 'X':
+			base = 16
+		case 'b', 'B':
+			base = 2
+		case 'o', 'O':
+			base = 8
+		}
+		if base != 10 {
+			literal = literal[2:]
+		}
+	}
+
+	return strconv.ParseInt(literal, base, 64)
+}
+
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	lit := &ast.FloatLiteral{Token: p.curToken}
+
+	// Anything too large for a float64 is an error.
+	value, err := strconv.ParseFloat(strings.ReplaceAll(p.curToken.Literal, "_", ""), 64)
+	if err != nil {
+		p.error(InvalidNumber, p.curToken, nil, "could not parse %q as float", p.curToken.Literal)
+		return nil
+	}
+
+	lit.Value = value
+
+	return lit
+}
+
+func (p *Parser) parseStringLiteral() ast.Expression {
+	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+}
+
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	str := &ast.InterpolatedString{Token: p.curToken}
+	addStringPart(str, p.curToken)
+
+	for {
+		if p.peekTokenIs(token.STRING_MIDDLE) || p.peekTokenIs(token.STRING_TAIL) {
+			p.error(EmptyInterpolation, p.peekToken, nil, "empty expression in string interpolation")
+		} else {
+			p.nextToken()
+			str.Parts = append(str.Parts, p.parseExpression(LOWEST))
+		}
+
+		p.nextToken()
+
+		switch p.curToken.Type {
+		case token.STRING_MIDDLE:
+			addStringPart(str, p.curToken)
+		case token.STRING_TAIL:
+			addStringPart(str, p.curToken)
+			return str
+		default:
+			if p.curTokenIs(token.ILLEGAL) {
+				p.panicking = true
+			} else {
+				p.error(UnexpectedToken, p.curToken, []token.TokenType{token.STRING_MIDDLE, token.STRING_TAIL},
+					"expected } to close string interpolation, got %s", p.curToken.Type)
+			}
+			return nil
+		}
+	}
+}
+
+// addStringPart adds the text of a STRING_HEAD, STRING_MIDDLE or STRING_TAIL
+// token to str, leaving out empty text.
+func addStringPart(str *ast.InterpolatedString, tok token.Token) {
+	if tok.Literal != "" {
+		str.Parts = append(str.Parts, &ast.StringLiteral{Token: tok, Value: tok.Literal})
+	}
+}
+
+func (p *Parser) parseBoolean() ast.Expression {
+	return &ast.Boolean{Token: p.curToken, Value: p.curTokenIs(token.TRUE)}
+}
+
+// parseGroupedExpression parses an expression wrapped in parentheses. The
+// parentheses are not kept in the AST, the grouping is already captured by the
+// shape of the tree.
+func (p *Parser) parseGroupedExpression() ast.Expression {
+	p.nextToken()
+
+	exp := p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return exp
+}
+
+func (p *Parser) parseIfExpression() ast.Expression {
+	expression := &ast.IfExpression{Token: p.curToken}
+
+	// The condition must be wrapped in parentheses.
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	expression.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Consequence = p.parseBlockStatement()
+
+	if !p.peekTokenIs(token.ELSE) {
+		return expression
+	}
+
+	p.nextToken()
+
+	// An else if is parsed as an alternative block holding just the nested if
+	// expression, so the chain can be any length.
+	if p.peekTokenIs(token.IF) {
+		p.nextToken()
+		block := &ast.BlockStatement{Token: p.curToken}
+		stmt := &ast.ExpressionStatement{Token: p.curToken}
+		stmt.Expression = p.parseIfExpression()
+		if stmt.Expression == nil {
+			return nil
+		}
+		block.Statements = []ast.Statement{stmt}
+		expression.Alternative = block
+
+		return expression
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Alternative = p.parseBlockStatement()
+
+	return expression
+}
+
+// parseBlockStatement parses statements until the closing RBRACE, which is
+// left as the current token.
+func (p *Parser) parseBlockStatement() *ast.BlockStatement {
+	block := &ast.BlockStatement{Token: p.curToken}
+	block.Statements = []ast.Statement{}
+
+	p.nextToken()
+
+	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) && !p.tooManyErrors {
+		start, braces := p.curToken, p.braces
+
+		stmt := p.parseStatement()
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+
+		if p.panicking {
+			p.synchronize(start, braces)
+			continue
+		}
+		p.nextToken()
+	}
+
+	// Running out of input means the block was never closed.
+	if p.curTokenIs(token.EOF) {
+		p.error(UnexpectedToken, p.curToken, []token.TokenType{token.RBRACE},
+			"expected %s to close block, got %s", token.RBRACE, token.EOF)
+	}
+
+	return block
+}
+
+func (p *Parser) parsePrefixExpression() ast.Expression {
+	return p.parsePrefixOperator(PREFIX)
+}
+
+// parsePrefixOperator parses a prefix operator whose operand binds at the given
+// precedence.
+func (p *Parser) parsePrefixOperator(precedence int) ast.Expression {
+	expression := &ast.PrefixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal,
+	}
+
+	// Advance past the operator and parse the operand.
+	p.nextToken()
+	expression.Right = p.parseExpression(precedence)
+
+	return expression
+}
+
+func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
+	expression := &ast.InfixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal,
+		Left:     left,
+	}
+
+	// Parse the right hand side with the precedence of this operator, so that
+	// operators of equal precedence associate to the left. Right associative
+	// operators such as POWER parse it with a lower precedence so that
+	// 2 ** 3 ** 2 is 2 ** (3 ** 2).
+	precedence := p.curPrecedence()
+	assoc := p.associativity[p.curToken.Type]
+	if assoc == AssocRight {
+		precedence--
+	}
+	p.nextToken()
+	p.operator = expression.Token
+	expression.Right = p.parseExpression(precedence)
+
+	return expression
+}
+
+// parseAssignExpression parses a compound assignment such as x += 1. The left
+// hand side must be an identifier, and assignments associate to the right.
+func (p *Parser) parseAssignExpression(left ast.Expression) ast.Expression {
+	name, ok := left.(*ast.Identifier)
+	if !ok {
+		// A nil left hand side has already been reported.
+		if left != nil {
+			p.error(InvalidAssignment, p.curToken, nil, "cannot assign to %s", left)
+		}
+		return nil
+	}
+
+	expression := &ast.AssignExpression{
+		Token:    p.curToken,
+		Name:     name,
+		Operator: p.curToken.Literal,
+	}
+
+	p.nextToken()
+	expression.Value = p.parseExpression(ASSIGN - 1)
+
+	return expression
+}
+
+func (p *Parser) parseFunctionLiteral() ast.Expression {
+	lit := &ast.FunctionLiteral{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	lit.Parameters = p.parseFunctionParameters()
+	if lit.Parameters == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	lit.Body = p.parseBlockStatement()
+
+	return lit
+}
+
+// parseFunctionParameters parses a comma separated list of identifiers up to
+// and including the closing RPAREN. It returns nil on error, and an empty
+// slice if there are no parameters.
+func (p *Parser) parseFunctionParameters() []*ast.Identifier {
+	identifiers := []*ast.Identifier{}
+
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		return identifiers
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	identifiers = append(identifiers, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		identifiers = append(identifiers, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return identifiers
+}
+
+func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
+	exp := &ast.CallExpression{Token: p.curToken, Function: function}
+
+	exp.Arguments = p.parseExpressionList(token.RPAREN)
+	if exp.Arguments == nil {
+		return nil
+	}
+
+	return exp
+}
+
+func (p *Parser) parseArrayLiteral() ast.Expression {
+	array := &ast.ArrayLiteral{Token: p.curToken}
+
+	array.Elements = p.parseExpressionList(token.RBRACKET)
+	if array.Elements == nil {
+		return nil
+	}
+
+	return array
+}
+
+func (p *Parser) parseHashLiteral() ast.Expression {
+	hash := &ast.HashLiteral{Token: p.curToken, Pairs: []ast.HashPair{}}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		key := p.parseExpression(LOWEST)
+
+		if !p.expectPeek(token.COLON) {
+			return nil
+		}
+
+		p.nextToken()
+		value := p.parseExpression(LOWEST)
+
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
+
+		// Pairs are separated by a COMMA, one after the last pair is allowed.
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return hash
+}
+
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+
+	p.nextToken()
+	exp.Index = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return exp
+}
+
+// parseExpressionList parses comma separated expressions up to and including
+// the end token, e.g. the arguments of a call or the elements of an array. It
+// returns nil on error, and an empty slice if there are no expressions.
+func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
+	list := []ast.Expression{}
+
+	if p.peekTokenIs(end) {
+		p.nextToken()
+		return list
+	}
+
+	p.nextToken()
+	list = append(list, p.parseExpression(LOWEST))
+
+	for p.peekTokenIs(token.COMMA) {
+		// Skip over the current expression and the COMMA.
+		p.nextToken()
+		p.nextToken()
+		list = append(list, p.parseExpression(LOWEST))
+	}
+
+	if !p.expectPeek(end) {
+		return nil
+	}
+
+	return list
+}
@@ -2,49 +2,55 @@ package parser
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/kevinglasson/monkey/ast"
 	"github.com/kevinglasson/monkey/lexer"
+	"github.com/kevinglasson/monkey/token"
 )
 
 func TestLetStatements(t *testing.T) {
-	input := `
-let x = 5;
-let y = 10;
-let foobar = 260991;
-`
-	l := lexer.New(input)
-	p := New(l)
-
-	program := p.ParseProgram()
-	checkParserErrors(t, p)
-	if program == nil {
-		t.Fatalf("ParseProgram return nil")
-	}
-	if len(program.Statements) != 3 {
-		t.Fatalf(
-			"program.Statements does not contain 3 statements. got %d",
-			len(program.Statements),
-		)
-	}
-
-	// Create an slice of expected identifiers.
 	tests := []struct {
+		input              string
 		expectedIdentifier string
+		expectedValue      string
 	}{
-		{"x"},
-		{"y"},
-		{"foobar"},
+		{"let x = y;", "x", "y"},
+		{"let y = z", "y", "z"},
+		{"let foobar = -bar;", "foobar", "(-bar)"},
+		{"let result = a + b * c;", "result", "(a + (b * c))"},
+		{"let five = 5;", "five", "5"},
+		{"let yes = true;", "yes", "true"},
+		{"let foobar = 260991;", "foobar", "260991"},
 	}
 
-	// For each expected identifier check that it is as expected in the
-	// programs statement slice.
-	for i, tt := range tests {
-		stmt := program.Statements[i]
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf(
+				"program.Statements does not contain 1 statements. got=%d",
+				len(program.Statements),
+			)
+		}
+
+		stmt := program.Statements[0]
 		if !testLetStatement(t, stmt, tt.expectedIdentifier) {
 			return
 		}
+
+		val := stmt.(*ast.LetStatement).Value
+		if val == nil {
+			t.Fatalf("letStmt.Value is nil")
+		}
+		if val.String() != tt.expectedValue {
+			t.Errorf("letStmt.Value wrong. expected=%q, got=%q", tt.expectedValue, val.String())
+		}
 	}
 }
 
@@ -98,30 +104,34 @@ func testLetStatement(t *testing.T, s ast.Statement, name string) bool {
 }
 
 func TestReturnStatement(t *testing.T) {
-	input := `
-return 5;
-return 10;
-return 993322;
-`
-	l := lexer.New(input)
-	p := New(l)
+	tests := []struct {
+		input         string
+		expectedValue string
+	}{
+		{"return x;", "x"},
+		{"return y", "y"},
+		{"return a * b - c;", "((a * b) - c)"},
+		{"return 5;", "5"},
+		{"return 993322;", "993322"},
+		{"return false;", "false"},
+	}
 
-	program := p.ParseProgram()
-	checkParserErrors(t, p)
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
 
-	if len(program.Statements) != 3 {
-		t.Fatalf(
-			"program.Statements does not contain 3 statements. got=%d",
-			len(program.Statements),
-		)
-	}
+		if len(program.Statements) != 1 {
+			t.Fatalf(
+				"program.Statements does not contain 1 statements. got=%d",
+				len(program.Statements),
+			)
+		}
 
-	// Assert type of each statement is ReturnStatement.
-	for _, stmt := range program.Statements {
-		returnStmt, ok := stmt.(*ast.ReturnStatement)
+		returnStmt, ok := program.Statements[0].(*ast.ReturnStatement)
 		if !ok {
-			t.Errorf("stmt not *ast.ReturnStatement. got %T", stmt)
-			continue
+			t.Fatalf("stmt not *ast.ReturnStatement. got %T", program.Statements[0])
 		}
 		if returnStmt.TokenLiteral() != "return" {
 			t.Errorf(
@@ -129,6 +139,58 @@ return 993322;
 				returnStmt.TokenLiteral(),
 			)
 		}
+		if returnStmt.ReturnValue == nil {
+			t.Fatalf("returnStmt.ReturnValue is nil")
+		}
+		if returnStmt.ReturnValue.String() != tt.expectedValue {
+			t.Errorf(
+				"returnStmt.ReturnValue wrong. expected=%q, got=%q",
+				tt.expectedValue, returnStmt.ReturnValue.String(),
+			)
+		}
+	}
+}
+
+func TestStatementsWithoutSemicolons(t *testing.T) {
+	input := `
+let x = a
+let y = b
+return x + y
+`
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	expected := "let x = a;let y = b;return (x + y);"
+	if program.String() != expected {
+		t.Errorf("program.String() wrong. expected=%q, got=%q", expected, program.String())
+	}
+}
+
+func TestStatementsEndingAtEOF(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let x =", "1:8: no prefix parse function for EOF found"},
+		{"let x", "1:6: expected next token to be =, got EOF"},
+		{"let", "1:4: expected next token to be IDENT, got EOF"},
+		{"return", "1:7: no prefix parse function for EOF found"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) != 1 {
+			t.Fatalf("input %q: expected 1 error, got=%d: %q", tt.input, len(errors), errors)
+		}
+		if errors[0] != tt.expectedError {
+			t.Errorf("input %q: wrong error. expected=%q, got=%q", tt.input, tt.expectedError, errors[0])
+		}
 	}
 }
 
@@ -162,3 +224,1214 @@ func TestIdentifierExpression(t *testing.T) {
 		t.Errorf("ident.TokenLiteral not %s. got=%s", "foobar", ident.TokenLiteral())
 	}
 }
+
+func TestParsingPrefixExpressions(t *testing.T) {
+	prefixTests := []struct {
+		input    string
+		operator string
+		value    string
+	}{
+		{"!foo;", "!", "foo"},
+		{"-bar;", "-", "bar"},
+	}
+
+	for _, tt := range prefixTests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf(
+				"program.Statements does not contain %d statements. got=%d",
+				1, len(program.Statements),
+			)
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+		}
+
+		exp, ok := stmt.Expression.(*ast.PrefixExpression)
+		if !ok {
+			t.Fatalf("stmt is not ast.PrefixExpression. got=%T", stmt.Expression)
+		}
+		if exp.Operator != tt.operator {
+			t.Fatalf("exp.Operator is not '%s'. got=%s", tt.operator, exp.Operator)
+		}
+		if !testIdentifier(t, exp.Right, tt.value) {
+			return
+		}
+	}
+}
+
+func TestParsingInfixExpressions(t *testing.T) {
+	infixTests := []struct {
+		input      string
+		leftValue  string
+		operator   string
+		rightValue string
+	}{
+		{"a + b;", "a", "+", "b"},
+		{"a - b;", "a", "-", "b"},
+		{"a * b;", "a", "*", "b"},
+		{"a / b;", "a", "/", "b"},
+		{"a > b;", "a", ">", "b"},
+		{"a < b;", "a", "<", "b"},
+		{"a == b;", "a", "==", "b"},
+		{"a != b;", "a", "!=", "b"},
+	}
+
+	for _, tt := range infixTests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf(
+				"program.Statements does not contain %d statements. got=%d",
+				1, len(program.Statements),
+			)
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+		}
+
+		exp, ok := stmt.Expression.(*ast.InfixExpression)
+		if !ok {
+			t.Fatalf("exp is not ast.InfixExpression. got=%T", stmt.Expression)
+		}
+		if !testIdentifier(t, exp.Left, tt.leftValue) {
+			return
+		}
+		if exp.Operator != tt.operator {
+			t.Fatalf("exp.Operator is not '%s'. got=%s", tt.operator, exp.Operator)
+		}
+		if !testIdentifier(t, exp.Right, tt.rightValue) {
+			return
+		}
+	}
+}
+
+func TestOperatorPrecedenceParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"-a * b", "((-a) * b)"},
+		{"!-a", "(!(-a))"},
+		{"a + b + c", "((a + b) + c)"},
+		{"a + b - c", "((a + b) - c)"},
+		{"a * b * c", "((a * b) * c)"},
+		{"a * b / c", "((a * b) / c)"},
+		{"a + b / c", "(a + (b / c))"},
+		{"a + b * c + d / e - f", "(((a + (b * c)) + (d / e)) - f)"},
+		{"a + b; -c * d", "(a + b)((-c) * d)"},
+		{"a > b == c < d", "((a > b) == (c < d))"},
+		{"a < b != c > d", "((a < b) != (c > d))"},
+		{"a + b * c == d * e + f", "((a + (b * c)) == ((d * e) + f))"},
+		{"5 > 4 == 3 < 4", "((5 > 4) == (3 < 4))"},
+		{"3 + 4 * 5 == 3 * 1 + 4 * 5", "((3 + (4 * 5)) == ((3 * 1) + (4 * 5)))"},
+		{"true", "true"},
+		{"false", "false"},
+		{"3 > 5 == false", "((3 > 5) == false)"},
+		{"3 < 5 == true", "((3 < 5) == true)"},
+		{"!true", "(!true)"},
+		{"1 + (2 + 3) + 4", "((1 + (2 + 3)) + 4)"},
+		{"(5 + 5) * 2", "((5 + 5) * 2)"},
+		{"2 / (5 + 5)", "(2 / (5 + 5))"},
+		{"(1 + 2) * -3", "((1 + 2) * (-3))"},
+		{"-(5 + 5)", "(-(5 + 5))"},
+		{"!(true == true)", "(!(true == true))"},
+		{"a + add(b * c) + d", "((a + add((b * c))) + d)"},
+		{"add(a, b, 1, 2 * 3, 4 + 5, add(6, 7 * 8))", "add(a, b, 1, (2 * 3), (4 + 5), add(6, (7 * 8)))"},
+		{"add(a + b + c * d / f + g)", "add((((a + b) + ((c * d) / f)) + g))"},
+		{"f(1)(2)", "f(1)(2)"},
+		{"a || b && c", "(a || (b && c))"},
+		{"a && b || c && d", "((a && b) || (c && d))"},
+		{"a == b && c != d", "((a == b) && (c != d))"},
+		{"a <= b == c >= d", "((a <= b) == (c >= d))"},
+		{"a | b ^ c & d", "(a | (b ^ (c & d)))"},
+		{"a & b == 0", "((a & b) == 0)"},
+		{"1 << 2 + 3", "(1 << (2 + 3))"},
+		{"a | b < c", "((a | b) < c)"},
+		{"a % b * c", "((a % b) * c)"},
+		{"2 ** 3 ** 2", "(2 ** (3 ** 2))"},
+		{"-2 ** 2", "(-(2 ** 2))"},
+		{"2 ** -1", "(2 ** (-1))"},
+		{"a * b ** c", "(a * (b ** c))"},
+		{"x += 1", "(x += 1)"},
+		{"x += y *= 2", "(x += (y *= 2))"},
+		{"x -= a || b", "(x -= (a || b))"},
+		{"-f(x)", "(-f(x))"},
+		{"a * [1, 2, 3, 4][b * c] * d", "((a * ([1, 2, 3, 4][(b * c)])) * d)"},
+		{"add(a * b[2], b[1], 2 * [1, 2][1])", "add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))"},
+		{"f(x)[0]", "(f(x)[0])"},
+		{"-a[0] ** 2", "(-((a[0]) ** 2))"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestNoPrefixParseFnError(t *testing.T) {
+	l := lexer.New("* a;")
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 error, got=%d: %q", len(errors), errors)
+	}
+	expected := "1:1: no prefix parse function for * found"
+	if errors[0] != expected {
+		t.Errorf("wrong error. expected=%q, got=%q", expected, errors[0])
+	}
+}
+
+func TestInvalidAssignmentTarget(t *testing.T) {
+	l := lexer.New("1 += 2")
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	expected := "1:3: cannot assign to 1"
+	if len(errors) != 1 || errors[0] != expected {
+		t.Fatalf("wrong errors. expected=%q, got=%q", expected, errors)
+	}
+}
+
+func TestIntegerLiteralExpression(t *testing.T) {
+	input := "5;"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program has not enough statements. got=%d", len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	testIntegerLiteral(t, stmt.Expression, 5)
+}
+
+func TestNumberLiteralValues(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"010", int64(10)},
+		{"1_000", int64(1000)},
+		{"0xff", int64(255)},
+		{"0b1010", int64(10)},
+		{"0o17", int64(15)},
+		{"0x7FFF_FFFF_FFFF_FFFF", int64(9223372036854775807)},
+		{"1.5", 1.5},
+		{"1_000.5", 1000.5},
+		{"2e3", 2000.0},
+		{"2.5E-1", 0.25},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		exp := program.Statements[0].(*ast.ExpressionStatement).Expression
+		switch expected := tt.expected.(type) {
+		case int64:
+			lit, ok := exp.(*ast.IntegerLiteral)
+			if !ok {
+				t.Fatalf("%q: exp not *ast.IntegerLiteral. got=%T", tt.input, exp)
+			}
+			if lit.Value != expected {
+				t.Errorf("%q: lit.Value not %d. got=%d", tt.input, expected, lit.Value)
+			}
+		case float64:
+			lit, ok := exp.(*ast.FloatLiteral)
+			if !ok {
+				t.Fatalf("%q: exp not *ast.FloatLiteral. got=%T", tt.input, exp)
+			}
+			if lit.Value != expected {
+				t.Errorf("%q: lit.Value not %g. got=%g", tt.input, expected, lit.Value)
+			}
+		}
+
+		if program.String() != tt.input {
+			t.Errorf("program.String() not %q. got=%q", tt.input, program.String())
+		}
+	}
+}
+
+func TestFloatLiteralOverflow(t *testing.T) {
+	l := lexer.New("1e400")
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	expected := `1:1: could not parse "1e400" as float`
+	if len(errors) != 1 || errors[0] != expected {
+		t.Fatalf("wrong errors. expected=%q, got=%q", expected, errors)
+	}
+}
+
+func TestIntegerLiteralOverflow(t *testing.T) {
+	input := "9223372036854775808;"
+
+	l := lexer.New(input)
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 error, got=%d: %q", len(errors), errors)
+	}
+	expected := `1:1: could not parse "9223372036854775808" as integer`
+	if errors[0] != expected {
+		t.Errorf("wrong error. expected=%q, got=%q", expected, errors[0])
+	}
+}
+
+func TestStringLiteralExpression(t *testing.T) {
+	input := `"hello\tworld";`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	literal, ok := stmt.Expression.(*ast.StringLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.StringLiteral. got=%T", stmt.Expression)
+	}
+
+	if literal.Value != "hello\tworld" {
+		t.Errorf("literal.Value not %q. got=%q", "hello\tworld", literal.Value)
+	}
+
+	if program.String() != input[:len(input)-1] {
+		t.Errorf("program.String() not %q. got=%q", input[:len(input)-1], program.String())
+	}
+}
+
+func TestInterpolatedStringExpression(t *testing.T) {
+	input := `"hello ${name}, you have ${count + 1} items"`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	str, ok := stmt.Expression.(*ast.InterpolatedString)
+	if !ok {
+		t.Fatalf("exp not *ast.InterpolatedString. got=%T", stmt.Expression)
+	}
+
+	if len(str.Parts) != 5 {
+		t.Fatalf("str.Parts does not contain 5 parts. got=%d", len(str.Parts))
+	}
+	for i, text := range map[int]string{0: "hello ", 2: ", you have ", 4: " items"} {
+		literal, ok := str.Parts[i].(*ast.StringLiteral)
+		if !ok {
+			t.Fatalf("str.Parts[%d] not *ast.StringLiteral. got=%T", i, str.Parts[i])
+		}
+		if literal.Value != text {
+			t.Errorf("str.Parts[%d] not %q. got=%q", i, text, literal.Value)
+		}
+	}
+	testIdentifier(t, str.Parts[1], "name")
+	testInfixExpression(t, str.Parts[3], "count", "+", 1)
+
+	expected := `"hello ${name}, you have ${(count + 1)} items"`
+	if program.String() != expected {
+		t.Errorf("program.String() not %q. got=%q", expected, program.String())
+	}
+}
+
+func TestInterpolatedStringErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`"a ${} b"`, "1:6: empty expression in string interpolation"},
+		{`"a ${b c} d"`, "1:8: expected } to close string interpolation, got IDENT"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Fatalf("expected errors for %q, got none", tt.input)
+		}
+		if errors[0] != tt.expectedError {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expectedError, errors[0])
+		}
+	}
+}
+
+func TestLexerErrorsAreReported(t *testing.T) {
+	l := lexer.New(`let s = "abc`)
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 error, got=%d: %q", len(errors), errors)
+	}
+	expected := "1:9: unterminated string"
+	if errors[0] != expected {
+		t.Errorf("wrong error. expected=%q, got=%q", expected, errors[0])
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedCode     ErrorCode
+		expectedStart    int
+		expectedEnd      int
+		expectedExpected []token.TokenType
+		expectedActual   token.TokenType
+	}{
+		{"let 5 = x", UnexpectedToken, 4, 5, []token.TokenType{token.IDENT}, token.INT},
+		{"x + ;", NoPrefixParseFn, 4, 5, nil, token.SEMICOLON},
+		{"fn() { x", UnexpectedToken, 8, 8, []token.TokenType{token.RBRACE}, token.EOF},
+		{"1 += 2", InvalidAssignment, 2, 4, nil, token.PLUS_ASSIGN},
+		{"99999999999999999999", InvalidNumber, 0, 20, nil, token.INT},
+		{`"a ${} b"`, EmptyInterpolation, 5, 9, nil, token.STRING_TAIL},
+		{"x = \"ab\ncd", LexError, 4, 10, nil, token.STRING},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errs := p.ParseErrors()
+		if len(errs) == 0 {
+			t.Fatalf("expected errors for %q, got none", tt.input)
+		}
+		err := errs[0]
+
+		if err.Code != tt.expectedCode {
+			t.Errorf("%q: wrong code. expected=%q, got=%q", tt.input, tt.expectedCode, err.Code)
+		}
+		if err.Start.Offset != tt.expectedStart || err.End.Offset != tt.expectedEnd {
+			t.Errorf("%q: wrong span. expected=%d-%d, got=%d-%d",
+				tt.input, tt.expectedStart, tt.expectedEnd, err.Start.Offset, err.End.Offset)
+		}
+		if !reflect.DeepEqual(err.Expected, tt.expectedExpected) {
+			t.Errorf("%q: wrong expected tokens. expected=%q, got=%q", tt.input, tt.expectedExpected, err.Expected)
+		}
+		if err.Actual.Type != tt.expectedActual {
+			t.Errorf("%q: wrong actual token. expected=%q, got=%q", tt.input, tt.expectedActual, err.Actual.Type)
+		}
+		if p.Errors()[0] != err.Error() {
+			t.Errorf("%q: Errors() does not match. expected=%q, got=%q", tt.input, err.Error(), p.Errors()[0])
+		}
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedErrors     []string
+		expectedStatements []string
+	}{
+		{
+			"let = 5; let y = 2; y",
+			[]string{"1:5: expected next token to be IDENT, got ="},
+			[]string{"let y = 2;", "y"},
+		},
+		{
+			"let x 5; let y = 2;",
+			[]string{"1:7: expected next token to be =, got INT"},
+			[]string{"let y = 2;"},
+		},
+		{
+			"let x = 1 +\nlet y = 2",
+			[]string{"2:1: no prefix parse function for LET found"},
+			[]string{"let y = 2;"},
+		},
+		{
+			"let f = fn(x { x }; return f;",
+			[]string{"1:14: expected next token to be ), got {"},
+			[]string{"return f;"},
+		},
+		{
+			"let f = fn() { let = 1; 2 }; f()",
+			[]string{"1:20: expected next token to be IDENT, got ="},
+			[]string{"let f = fn() 2;", "f()"},
+		},
+		{
+			"x + * y; z",
+			[]string{"1:5: no prefix parse function for * found"},
+			[]string{"z"},
+		},
+		{
+			"f(g(1 2), 3); let y = 2;",
+			[]string{"1:7: expected next token to be ), got INT"},
+			[]string{"let y = 2;"},
+		},
+		{
+			"f(fn() { 1 }, 2 3); let y = 2;",
+			[]string{"1:17: expected next token to be ), got INT"},
+			[]string{"let y = 2;"},
+		},
+		{
+			`let h = {"a" 1, "b": 2}; let y = 2;`,
+			[]string{"1:14: expected next token to be :, got INT"},
+			[]string{"let y = 2;"},
+		},
+		{
+			`fn() { let h = {"a": {1 2}}; h }`,
+			[]string{"1:25: expected next token to be :, got INT"},
+			[]string{"fn() let h = ;h"},
+		},
+		{
+			"add(1, 2; let z = 3",
+			[]string{"1:9: expected next token to be ), got ;"},
+			[]string{"let z = 3;"},
+		},
+		{
+			"let a = ; let b 1; c",
+			[]string{
+				"1:9: no prefix parse function for ; found",
+				"1:17: expected next token to be =, got INT",
+			},
+			[]string{"c"},
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+
+		if !reflect.DeepEqual(p.Errors(), tt.expectedErrors) {
+			t.Errorf("%q: wrong errors.\nexpected=%q\ngot=     %q", tt.input, tt.expectedErrors, p.Errors())
+		}
+
+		// The statements after the mistake are parsed as normal, the broken
+		// ones before them are not checked.
+		n := len(program.Statements) - len(tt.expectedStatements)
+		if n < 0 {
+			t.Fatalf("%q: expected at least %d statements, got=%d", tt.input, len(tt.expectedStatements), len(program.Statements))
+		}
+		for i, expected := range tt.expectedStatements {
+			if got := program.Statements[n+i].String(); got != expected {
+				t.Errorf("%q: wrong statement. expected=%q, got=%q", tt.input, expected, got)
+			}
+		}
+	}
+}
+
+func TestMaxErrors(t *testing.T) {
+	input := strings.Repeat("let = 1;\n", 20)
+
+	tests := []struct {
+		max           int
+		expectedCount int
+	}{
+		{DefaultMaxErrors, DefaultMaxErrors + 1},
+		{3, 4},
+		{0, 20},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(input)
+		p := New(l)
+		if tt.max != DefaultMaxErrors {
+			p.SetMaxErrors(tt.max)
+		}
+		p.ParseProgram()
+
+		errs := p.ParseErrors()
+		if len(errs) != tt.expectedCount {
+			t.Fatalf("max %d: expected %d errors, got=%d", tt.max, tt.expectedCount, len(errs))
+		}
+		if tt.max <= 0 {
+			continue
+		}
+		last := errs[len(errs)-1]
+		if last.Code != TooManyErrors || last.Error() != fmt.Sprintf("%d:5: too many errors", tt.max+1) {
+			t.Errorf("max %d: wrong last error. got=%q (%s)", tt.max, last, last.Code)
+		}
+	}
+}
+
+func TestIllegalTokensAreReportedOnce(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"let x = @;", []string{"1:9: unexpected character '@'"}},
+		{"1 + $", []string{"1:5: unexpected character '$'"}},
+		{"f(1, 0b2)", []string{"1:6: invalid digit '2' in binary literal"}},
+		{"let x = 1 + 0x;\nlet y = `;", []string{
+			"1:13: hexadecimal literal has no digits",
+			"2:9: unexpected character '`'",
+		}},
+		{"let x @ = 5; x", []string{"1:7: unexpected character '@'"}},
+		{"let x = (1 @ 2)", []string{"1:12: unexpected character '@'"}},
+		{"fn(a @ b) { a }", []string{"1:6: unexpected character '@'"}},
+		{`"a ${1 @ 2} b"`, []string{"1:8: unexpected character '@'"}},
+		{"let x @ = 5; let y = ;", []string{
+			"1:7: unexpected character '@'",
+			"1:22: no prefix parse function for ; found",
+		}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if fmt.Sprintf("%q", errors) != fmt.Sprintf("%q", tt.expected) {
+			t.Errorf("input %q: wrong errors. expected=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestBooleanExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"true;", true},
+		{"false;", false},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program has not enough statements. got=%d", len(program.Statements))
+		}
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+		}
+
+		testBooleanLiteral(t, stmt.Expression, tt.expected)
+	}
+}
+
+func TestParsingLiteralInfixExpressions(t *testing.T) {
+	infixTests := []struct {
+		input      string
+		leftValue  interface{}
+		operator   string
+		rightValue interface{}
+	}{
+		{"5 + 5;", 5, "+", 5},
+		{"5 * 5;", 5, "*", 5},
+		{"5 < 5;", 5, "<", 5},
+		{"5 != 5;", 5, "!=", 5},
+		{"true == true", true, "==", true},
+		{"true != false", true, "!=", false},
+		{"a + 10", "a", "+", 10},
+	}
+
+	for _, tt := range infixTests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program has not enough statements. got=%d", len(program.Statements))
+		}
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+		}
+
+		if !testInfixExpression(t, stmt.Expression, tt.leftValue, tt.operator, tt.rightValue) {
+			return
+		}
+	}
+}
+
+func TestIfExpression(t *testing.T) {
+	input := `if (x < y) { x }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statements. got=%d", len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.IfExpression. got=%T", stmt.Expression)
+	}
+
+	if !testInfixExpression(t, exp.Condition, "x", "<", "y") {
+		return
+	}
+
+	if len(exp.Consequence.Statements) != 1 {
+		t.Errorf("consequence is not 1 statements. got=%d", len(exp.Consequence.Statements))
+	}
+
+	consequence, ok := exp.Consequence.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("Statements[0] is not ast.ExpressionStatement. got=%T", exp.Consequence.Statements[0])
+	}
+
+	if !testIdentifier(t, consequence.Expression, "x") {
+		return
+	}
+
+	if exp.Alternative != nil {
+		t.Errorf("exp.Alternative was not nil. got=%+v", exp.Alternative)
+	}
+}
+
+func TestIfElseExpression(t *testing.T) {
+	input := `if (x < y) { x } else { y }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statements. got=%d", len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.IfExpression. got=%T", stmt.Expression)
+	}
+
+	if !testInfixExpression(t, exp.Condition, "x", "<", "y") {
+		return
+	}
+
+	consequence, ok := exp.Consequence.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("Statements[0] is not ast.ExpressionStatement. got=%T", exp.Consequence.Statements[0])
+	}
+	if !testIdentifier(t, consequence.Expression, "x") {
+		return
+	}
+
+	if exp.Alternative == nil || len(exp.Alternative.Statements) != 1 {
+		t.Fatalf("exp.Alternative does not contain 1 statements. got=%+v", exp.Alternative)
+	}
+	alternative, ok := exp.Alternative.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("Statements[0] is not ast.ExpressionStatement. got=%T", exp.Alternative.Statements[0])
+	}
+	if !testIdentifier(t, alternative.Expression, "y") {
+		return
+	}
+}
+
+func TestElseIfChain(t *testing.T) {
+	input := `if (a) { 1 } else if (b) { 2 } else if (c) { 3 } else { 4 }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statements. got=%d", len(program.Statements))
+	}
+
+	expected := "ifa 1else ifb 2else ifc 3else 4"
+	if program.String() != expected {
+		t.Errorf("program.String() wrong. expected=%q, got=%q", expected, program.String())
+	}
+
+	exp := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IfExpression)
+	for _, cond := range []string{"b", "c"} {
+		nested, ok := exp.Alternative.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IfExpression)
+		if !ok {
+			t.Fatalf("alternative is not a nested ast.IfExpression. got=%s", exp.Alternative)
+		}
+		if !testIdentifier(t, nested.Condition, cond) {
+			return
+		}
+		exp = nested
+	}
+	if !testLiteralExpression(t, exp.Alternative.Statements[0].(*ast.ExpressionStatement).Expression, 4) {
+		return
+	}
+}
+
+func TestUnterminatedBlock(t *testing.T) {
+	l := lexer.New("if (x) { y")
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 error, got=%d: %q", len(errors), errors)
+	}
+	expected := "1:11: expected } to close block, got EOF"
+	if errors[0] != expected {
+		t.Errorf("wrong error. expected=%q, got=%q", expected, errors[0])
+	}
+}
+
+func TestFunctionLiteralParsing(t *testing.T) {
+	input := `fn(x, y) { x + y; }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statements. got=%d", len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	function, ok := stmt.Expression.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.FunctionLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(function.Parameters) != 2 {
+		t.Fatalf("function literal parameters wrong. want 2, got=%d", len(function.Parameters))
+	}
+
+	testLiteralExpression(t, function.Parameters[0], "x")
+	testLiteralExpression(t, function.Parameters[1], "y")
+
+	if len(function.Body.Statements) != 1 {
+		t.Fatalf("function.Body.Statements has not 1 statements. got=%d", len(function.Body.Statements))
+	}
+
+	bodyStmt, ok := function.Body.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("function body stmt is not ast.ExpressionStatement. got=%T", function.Body.Statements[0])
+	}
+
+	testInfixExpression(t, bodyStmt.Expression, "x", "+", "y")
+}
+
+func TestFunctionParameterParsing(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedParams []string
+	}{
+		{input: "fn() {};", expectedParams: []string{}},
+		{input: "fn(x) {};", expectedParams: []string{"x"}},
+		{input: "fn(x, y, z) {};", expectedParams: []string{"x", "y", "z"}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		function := stmt.Expression.(*ast.FunctionLiteral)
+
+		if len(function.Parameters) != len(tt.expectedParams) {
+			t.Errorf(
+				"length parameters wrong. want %d, got=%d",
+				len(tt.expectedParams), len(function.Parameters),
+			)
+		}
+
+		for i, ident := range tt.expectedParams {
+			testLiteralExpression(t, function.Parameters[i], ident)
+		}
+	}
+}
+
+func TestCallExpressionParsing(t *testing.T) {
+	input := "add(1, 2 * 3, 4 + 5);"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statements. got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("stmt is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.CallExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.CallExpression. got=%T", stmt.Expression)
+	}
+
+	if !testIdentifier(t, exp.Function, "add") {
+		return
+	}
+
+	if len(exp.Arguments) != 3 {
+		t.Fatalf("wrong length of arguments. got=%d", len(exp.Arguments))
+	}
+
+	testLiteralExpression(t, exp.Arguments[0], 1)
+	testInfixExpression(t, exp.Arguments[1], 2, "*", 3)
+	testInfixExpression(t, exp.Arguments[2], 4, "+", 5)
+}
+
+func TestFunctionDefinitionAndCall(t *testing.T) {
+	input := "let add = fn(a, b) { a + b }; add(1, 2)"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements does not contain 2 statements. got=%d", len(program.Statements))
+	}
+
+	expected := "let add = fn(a, b) (a + b);add(1, 2)"
+	if program.String() != expected {
+		t.Errorf("program.String() wrong. expected=%q, got=%q", expected, program.String())
+	}
+}
+
+func testInfixExpression(
+	t *testing.T,
+	exp ast.Expression,
+	left interface{},
+	operator string,
+	right interface{},
+) bool {
+	opExp, ok := exp.(*ast.InfixExpression)
+	if !ok {
+		t.Errorf("exp is not ast.InfixExpression. got=%T(%s)", exp, exp)
+		return false
+	}
+
+	if !testLiteralExpression(t, opExp.Left, left) {
+		return false
+	}
+
+	if opExp.Operator != operator {
+		t.Errorf("exp.Operator is not '%s'. got=%q", operator, opExp.Operator)
+		return false
+	}
+
+	if !testLiteralExpression(t, opExp.Right, right) {
+		return false
+	}
+
+	return true
+}
+
+// testLiteralExpression dispatches to the right literal helper based on the
+// type of the expected value.
+func testLiteralExpression(t *testing.T, exp ast.Expression, expected interface{}) bool {
+	switch v := expected.(type) {
+	case int:
+		return testIntegerLiteral(t, exp, int64(v))
+	case int64:
+		return testIntegerLiteral(t, exp, v)
+	case string:
+		return testIdentifier(t, exp, v)
+	case bool:
+		return testBooleanLiteral(t, exp, v)
+	}
+	t.Errorf("type of exp not handled. got=%T", exp)
+	return false
+}
+
+func testIntegerLiteral(t *testing.T, il ast.Expression, value int64) bool {
+	integ, ok := il.(*ast.IntegerLiteral)
+	if !ok {
+		t.Errorf("il not *ast.IntegerLiteral. got=%T", il)
+		return false
+	}
+
+	if integ.Value != value {
+		t.Errorf("integ.Value not %d. got=%d", value, integ.Value)
+		return false
+	}
+
+	if integ.TokenLiteral() != fmt.Sprintf("%d", value) {
+		t.Errorf("integ.TokenLiteral not %d. got=%s", value, integ.TokenLiteral())
+		return false
+	}
+
+	return true
+}
+
+func testBooleanLiteral(t *testing.T, exp ast.Expression, value bool) bool {
+	bo, ok := exp.(*ast.Boolean)
+	if !ok {
+		t.Errorf("exp not *ast.Boolean. got=%T", exp)
+		return false
+	}
+
+	if bo.Value != value {
+		t.Errorf("bo.Value not %t. got=%t", value, bo.Value)
+		return false
+	}
+
+	if bo.TokenLiteral() != fmt.Sprintf("%t", value) {
+		t.Errorf("bo.TokenLiteral not %t. got=%s", value, bo.TokenLiteral())
+		return false
+	}
+
+	return true
+}
+
+func testIdentifier(t *testing.T, exp ast.Expression, value string) bool {
+	ident, ok := exp.(*ast.Identifier)
+	if !ok {
+		t.Errorf("exp not *ast.Identifier. got=%T", exp)
+		return false
+	}
+
+	if ident.Value != value {
+		t.Errorf("ident.Value not %s. got=%s", value, ident.Value)
+		return false
+	}
+
+	if ident.TokenLiteral() != value {
+		t.Errorf("ident.TokenLiteral not %s. got=%s", value, ident.TokenLiteral())
+		return false
+	}
+
+	return true
+}
+
+func TestParsingArrayLiterals(t *testing.T) {
+	input := "[1, 2 * 2, fn(x) { x }]"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	array, ok := stmt.Expression.(*ast.ArrayLiteral)
+	if !ok {
+		t.Fatalf("exp not ast.ArrayLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(array.Elements) != 3 {
+		t.Fatalf("len(array.Elements) not 3. got=%d", len(array.Elements))
+	}
+
+	testIntegerLiteral(t, array.Elements[0], 1)
+	testInfixExpression(t, array.Elements[1], 2, "*", 2)
+	if _, ok := array.Elements[2].(*ast.FunctionLiteral); !ok {
+		t.Errorf("array.Elements[2] not ast.FunctionLiteral. got=%T", array.Elements[2])
+	}
+}
+
+func TestParsingEmptyArrayLiteral(t *testing.T) {
+	l := lexer.New("[]")
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	array, ok := stmt.Expression.(*ast.ArrayLiteral)
+	if !ok {
+		t.Fatalf("exp not ast.ArrayLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(array.Elements) != 0 {
+		t.Errorf("len(array.Elements) not 0. got=%d", len(array.Elements))
+	}
+}
+
+func TestParsingIndexExpressions(t *testing.T) {
+	input := "myArray[1 + 1]"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	indexExp, ok := stmt.Expression.(*ast.IndexExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.IndexExpression. got=%T", stmt.Expression)
+	}
+
+	if !testIdentifier(t, indexExp.Left, "myArray") {
+		return
+	}
+
+	testInfixExpression(t, indexExp.Index, 1, "+", 1)
+}
+
+func TestUnclosedIndexExpression(t *testing.T) {
+	l := lexer.New("a[1; b")
+	p := New(l)
+	p.ParseProgram()
+
+	expected := []string{"1:4: expected next token to be ], got ;"}
+	if !reflect.DeepEqual(p.Errors(), expected) {
+		t.Errorf("wrong errors. expected=%q, got=%q", expected, p.Errors())
+	}
+}
+
+func TestParsingHashLiterals(t *testing.T) {
+	input := `{"one": 1, "two": 2, "three": 3}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	expected := []struct {
+		key   string
+		value int64
+	}{
+		{"one", 1},
+		{"two", 2},
+		{"three", 3},
+	}
+
+	if len(hash.Pairs) != len(expected) {
+		t.Fatalf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+	}
+
+	for i, pair := range hash.Pairs {
+		literal, ok := pair.Key.(*ast.StringLiteral)
+		if !ok {
+			t.Errorf("key is not ast.StringLiteral. got=%T", pair.Key)
+			continue
+		}
+		if literal.Value != expected[i].key {
+			t.Errorf("hash.Pairs[%d] key wrong. expected=%q, got=%q", i, expected[i].key, literal.Value)
+		}
+		testIntegerLiteral(t, pair.Value, expected[i].value)
+	}
+}
+
+func TestParsingEmptyHashLiteral(t *testing.T) {
+	l := lexer.New("{}")
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(hash.Pairs) != 0 {
+		t.Errorf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+	}
+}
+
+func TestParsingHashLiteralsWithExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"one": 0 + 1, "two": 10 - 8, 3: 15 / 5}`, `{"one": (0 + 1), "two": (10 - 8), 3: (15 / 5)}`},
+		{`{true: fn(x) { x }, "a": {"b": [1]},}`, `{true: fn(x) x, "a": {"b": [1]}}`},
+		{`let h = {"a": 1}["a"]`, `let h = ({"a": 1}["a"]);`},
+		{`if (x) { {"a": 1} } else { {} }`, `ifx {"a": 1}else {}`},
+		{`"${ {"a": 1}["a"] }"`, `"${({"a": 1}["a"])}"`},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestStringWithParseErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 +", "(1 + )"},
+		{"-", "(-)"},
+		{"+ 1", ""},
+		{"f(1, ;", ""},
+		{"[1, *, 2]", "[1, , 2]"},
+		{"a[]", ""},
+		{`{"a": }`, ""},
+		{"x += ;", "(x += )"},
+		{`"a ${} b"`, `"a  b"`},
+		{"if (x) { 1 +; }", "ifx (1 + )"},
+		{"let x = 1 * ;", "let x = (1 * );"},
+		{"return -;", "return (-);"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("%q: expected parse errors, got none", tt.input)
+		}
+		if program.String() != tt.expected {
+			t.Errorf("%q: expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+}
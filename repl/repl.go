@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/kevinglasson/monkey/evaluator"
 	"github.com/kevinglasson/monkey/lexer"
-	"github.com/kevinglasson/monkey/token"
+	"github.com/kevinglasson/monkey/object"
+	"github.com/kevinglasson/monkey/parser"
 )
 
 // PROMPT is the REPL prompt constant.
@@ -16,10 +18,13 @@ const PROMPT = ">> "
 func Start(in io.Reader, out io.Writer) {
 	// Create a scanner to read from the reader.
 	scanner := bufio.NewScanner(in)
+	// One environment for the whole session so that bindings made on earlier
+	// lines are visible on later ones.
+	env := object.NewEnvironment()
 
 	// Loop forever.
 	for {
-		fmt.Fprintf(out, PROMPT)
+		fmt.Fprint(out, PROMPT)
 
 		// Scan until the end.
 		scanned := scanner.Scan()
@@ -30,11 +35,27 @@ func Start(in io.Reader, out io.Writer) {
 
 		line := scanner.Text()
 		l := lexer.New(line)
+		p := parser.New(l)
 
-		// Loop => init tok to the first token, while not EOF and call
-		// `NextToken` on each iteration
-		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
-			fmt.Fprintf(out, "%+v\n", tok)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			printParserErrors(out, p.Errors())
+			continue
 		}
+
+		// Let statements evaluate to nothing so there is nothing to print.
+		evaluated := evaluator.Eval(program, env)
+		if evaluated != nil {
+			io.WriteString(out, evaluated.Inspect())
+			io.WriteString(out, "\n")
+		}
+	}
+}
+
+// printParserErrors writes each parser error on it's own line.
+func printParserErrors(out io.Writer, errors []string) {
+	io.WriteString(out, "parser errors:\n")
+	for _, msg := range errors {
+		io.WriteString(out, "\t"+msg+"\n")
 	}
 }
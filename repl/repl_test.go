@@ -0,0 +1,32 @@
+package repl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	input := `let x = 5;
+x * 2
+let add = fn(a, b) { a + b };
+add(x, 1)
+let y = ;
+y
+`
+
+	var out bytes.Buffer
+	Start(strings.NewReader(input), &out)
+
+	expected := ">> " +
+		">> 10\n" +
+		">> " +
+		">> 6\n" +
+		">> parser errors:\n\t1:9: no prefix parse function for ; found\n" +
+		">> ERROR: identifier not found: y\n" +
+		">> "
+
+	if out.String() != expected {
+		t.Errorf("wrong output.\nexpected=%q\ngot=     %q", expected, out.String())
+	}
+}
@@ -1,12 +1,105 @@
 package token
 
+import (
+	"fmt"
+	"strings"
+)
+
 // TokenType is a constant representing the token types that are lexed.
 type TokenType string
 
-// Token is a struct to package a lexed token type with it's literal value.
+// Token is a struct to package a lexed token type with it's literal value and
+// the positions it starts and ends at in the source.
 type Token struct {
 	Type    TokenType
 	Literal string
+	Pos     Position
+	// End is the position just after the token.
+	End Position
+
+	// Raw, Leading and Trailing are only set when the lexer keeps trivia. Raw
+	// is the token exactly as it appears in the source, which differs from
+	// the Literal for strings. Leading is the trivia between the previous
+	// token's Trailing trivia and this token, Trailing is the trivia after
+	// this token up to and including the end of the line.
+	Raw      string
+	Leading  []Trivia
+	Trailing []Trivia
+}
+
+// Source returns the token as it appears in the source along with it's trivia.
+// Concatenating the Source of every token up to and including EOF reproduces
+// the source exactly.
+func (t Token) Source() string {
+	var out strings.Builder
+
+	for _, tr := range t.Leading {
+		out.WriteString(tr.Text)
+	}
+	out.WriteString(t.Raw)
+	for _, tr := range t.Trailing {
+		out.WriteString(tr.Text)
+	}
+
+	return out.String()
+}
+
+// TriviaKind is a constant representing the kinds of trivia.
+type TriviaKind string
+
+// Trivia is a piece of the source between tokens that doesn't change the
+// meaning of the program, such as whitespace and comments.
+type Trivia struct {
+	Kind TriviaKind
+	Text string
+}
+
+const (
+	// WHITESPACE is the TriviaKind for a run of spaces, tabs and carriage
+	// returns.
+	WHITESPACE = "WHITESPACE"
+	// NEWLINE is the TriviaKind for a single "\n" or "\r\n".
+	NEWLINE = "NEWLINE"
+	// LINE_COMMENT is the TriviaKind for a // comment, without the newline
+	// ending it.
+	LINE_COMMENT = "LINE_COMMENT"
+	// BLOCK_COMMENT is the TriviaKind for a /* */ comment, including any
+	// nested comments.
+	BLOCK_COMMENT = "BLOCK_COMMENT"
+	// SHEBANG is the TriviaKind for a #! line at the very start of the source,
+	// without the newline ending it.
+	SHEBANG = "SHEBANG"
+)
+
+// Position is a location in the source. Line and Column start at 1 and the
+// Column is counted in bytes, Offset is the byte offset from the start of the
+// source starting at 0.
+type Position struct {
+	// Filename is optional, it is empty when the source didn't come from a
+	// file.
+	Filename string
+	Offset   int
+	Line     int
+	Column   int
+}
+
+// IsValid reports whether the position has been set.
+func (p Position) IsValid() bool { return p.Line > 0 }
+
+// String formats the position as file:line:column, leaving out the file name
+// if there isn't one, or "-" if the position isn't valid.
+func (p Position) String() string {
+	s := p.Filename
+	if p.IsValid() {
+		if s != "" {
+			s += ":"
+		}
+		s += fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	if s == "" {
+		s = "-"
+	}
+	return s
 }
 
 const (
@@ -23,6 +116,20 @@ const (
 	IDENT = "IDENT"
 	// INT is the TokenType for integer numbers.
 	INT = "INT"
+	// FLOAT is the TokenType for floating point numbers.
+	FLOAT = "FLOAT"
+	// STRING is the TokenType for double quoted strings, the literal is the
+	// string with the escape sequences decoded.
+	STRING = "STRING"
+	// STRING_HEAD is the TokenType for the part of an interpolated string
+	// before the first ${, it is followed by the tokens of the expression.
+	STRING_HEAD = "STRING_HEAD"
+	// STRING_MIDDLE is the TokenType for the part of an interpolated string
+	// between the } ending one expression and the ${ starting the next.
+	STRING_MIDDLE = "STRING_MIDDLE"
+	// STRING_TAIL is the TokenType for the part of an interpolated string
+	// after the } ending the last expression.
+	STRING_TAIL = "STRING_TAIL"
 	// TRUE
 	TRUE = "TRUE"
 	// FALSE
@@ -56,6 +163,37 @@ const (
 	EQ = "=="
 	// NEQ is the TokenType for the not equal operator.
 	NEQ = "!="
+	// LTE is the TokenType for the less than or equal comparison.
+	LTE = "<="
+	// GTE is the TokenType for the greater than or equal comparison.
+	GTE = ">="
+	// AND is the TokenType for the short-circuiting logical and.
+	AND = "&&"
+	// OR is the TokenType for the short-circuiting logical or.
+	OR = "||"
+	// PERCENT is the TokenType for the modulo operation.
+	PERCENT = "%"
+	// POWER is the TokenType for the exponentiation operation.
+	POWER = "**"
+	// AMPERSAND is the TokenType for the bitwise and operation.
+	AMPERSAND = "&"
+	// PIPE is the TokenType for the bitwise or operation.
+	PIPE = "|"
+	// CARET is the TokenType for the bitwise exclusive or operation.
+	CARET = "^"
+	// SHL is the TokenType for the left shift operation.
+	SHL = "<<"
+	// SHR is the TokenType for the right shift operation.
+	SHR = ">>"
+
+	// PLUS_ASSIGN is the TokenType for adding to a variable.
+	PLUS_ASSIGN = "+="
+	// MINUS_ASSIGN is the TokenType for subtracting from a variable.
+	MINUS_ASSIGN = "-="
+	// ASTERISK_ASSIGN is the TokenType for multiplying a variable.
+	ASTERISK_ASSIGN = "*="
+	// SLASH_ASSIGN is the TokenType for dividing a variable.
+	SLASH_ASSIGN = "/="
 
 	// Delimiters.
 
@@ -63,6 +201,8 @@ const (
 	COMMA = ","
 	// SEMICOLON is the TokenType to mark the termination of a statement.
 	SEMICOLON = ";"
+	// COLON is the TokenType to separate a key from it's value.
+	COLON = ":"
 
 	// LPAREN is the TokenType to mark the left parentheses.
 	LPAREN = "("
@@ -72,6 +212,10 @@ const (
 	LBRACE = "{"
 	// RBRACE is the TokenType to mark the right brace.
 	RBRACE = "}"
+	// LBRACKET is the TokenType to mark the left bracket.
+	LBRACKET = "["
+	// RBRACKET is the TokenType to mark the right bracket.
+	RBRACKET = "]"
 
 	// Keywords.
 